@@ -0,0 +1,130 @@
+//
+//  algorithm.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+// Package algorithm defines the interface between the client runtime and the
+// distributed algorithms it hosts. Algorithms register a factory under a name
+// and the server picks one of these names for a run.
+package algorithm
+
+import "errors"
+import "fmt"
+import "sort"
+import "sync"
+
+type Host interface {
+	SendMessage(sender string, receiver string, command uint8, value interface{})
+}
+
+// Environment is everything an algorithm instance knows about its node.
+type Environment struct {
+	Host      Host
+	ID        string
+	Neighbors []string           // only the successors on directed links
+	Weights   map[string]float64 // weight of the link to each neighbor
+	Value     float64            // per-node value, e.g. for aggregates
+}
+
+type Algorithm interface {
+	// Init is called once after the overlay is wired up and before any
+	// message is delivered.
+	Init(environment Environment)
+
+	// HandleMessage receives every message addressed to the node that is not
+	// handled by the runtime itself, including InitCommand from the server.
+	// When hosted by Instances the messages arrive one at a time and in the
+	// order they were received, which keeps every link FIFO; Result may still
+	// be called concurrently.
+	HandleMessage(sender string, receiver string, command uint8, value interface{})
+
+	// Result is sent to the server after the run completed. The value must be
+	// registered with encoding/gob.
+	Result() interface{}
+}
+
+// MessageCounter can be implemented by results to report how many messages
+// the node sent, which the server sums up after a run.
+type MessageCounter interface {
+	MessageCount() int
+}
+
+// DirectedAlgorithm is implemented by algorithms that only send along the
+// direction of a link. On a directed graph Environment.Neighbors lists the
+// successors of the node only, every other algorithm needs undirected links.
+type DirectedAlgorithm interface {
+	SupportsDirectedLinks() bool
+}
+
+// DistanceResult is implemented by results that know the node's distance
+// from the root of the run.
+type DistanceResult interface {
+	Distance() float64
+}
+
+// TreeResult is implemented by results of algorithms that build a spanning
+// tree. Parent returns the node's own ID for the root and an empty string for
+// nodes the traversal never reached.
+type TreeResult interface {
+	Parent() string
+}
+
+// StatusReporter is implemented by algorithms that expose their state while
+// they run, e.g. to find out why a run stalls. The value must be registered
+// with encoding/gob.
+type StatusReporter interface {
+	Status() interface{}
+}
+
+type Factory func() Algorithm
+
+var ErrUnknownAlgorithm = errors.New("unknown algorithm")
+
+var factories = make(map[string]Factory)
+var factoryGuard = new(sync.Mutex)
+
+func Register(name string, factory Factory) {
+
+	factoryGuard.Lock()
+	factories[name] = factory
+	factoryGuard.Unlock()
+}
+
+func New(name string) (Algorithm, error) {
+
+	factoryGuard.Lock()
+	var factory, registered = factories[name]
+	factoryGuard.Unlock()
+
+	if !registered {
+
+		return nil, fmt.Errorf("%w: %q", ErrUnknownAlgorithm, name)
+	}
+	return factory(), nil
+}
+
+func IsRegistered(name string) bool {
+
+	factoryGuard.Lock()
+	var _, registered = factories[name]
+	factoryGuard.Unlock()
+
+	return registered
+}
+
+func Names() []string {
+
+	factoryGuard.Lock()
+	var names []string
+	for name := range factories {
+
+		names = append(names, name)
+	}
+	factoryGuard.Unlock()
+
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,233 @@
+//
+//  algorithmtest.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+// Package algorithmtest simulates the overlay for tests of the algorithms.
+// Every link is FIFO like the reliable links of the clients, but the links are
+// delivered in a random order drawn from a seed, so a failing order can be
+// replayed.
+package algorithmtest
+
+import . "fmt"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/graph"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/message"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/bfs/command"
+
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/algorithm"
+
+import "math/rand"
+import "sort"
+import "sync"
+
+// Sent is a message as the network saw it.
+type Sent struct {
+	Sender   string
+	Receiver string
+	Command  uint8
+	Value    interface{}
+	InFlight int // messages between the nodes not delivered yet when a message to the server was sent
+}
+
+// Network delivers the messages between the attached nodes. Messages to the
+// server are recorded instead, except for RelayCommand, which the network
+// forwards like the server does.
+type Network struct {
+	guard    sync.Mutex
+	random   *rand.Rand
+	nodes    map[string]algorithm.MessageHandler
+	links    map[[2]string][]Sent
+	inFlight int
+	server   []Sent
+}
+
+func NetworkWith(seed int64) *Network {
+
+	var network = new(Network)
+	network.random = rand.New(rand.NewSource(seed))
+	network.nodes = make(map[string]algorithm.MessageHandler)
+	network.links = make(map[[2]string][]Sent)
+	return network
+}
+
+// Attach makes the handler the node with the given ID.
+func (network *Network) Attach(id string, handler algorithm.MessageHandler) {
+
+	network.guard.Lock()
+	network.nodes[id] = handler
+	network.guard.Unlock()
+}
+
+func (network *Network) SendMessage(sender string, receiver string, command uint8, value interface{}) {
+
+	network.guard.Lock()
+	defer network.guard.Unlock()
+
+	if receiver == "server" && command != RelayCommand {
+
+		network.server = append(network.server, Sent{Sender: sender, Receiver: receiver, Command: command, Value: value, InFlight: network.inFlight})
+		return
+	}
+	var link = [2]string{sender, receiver}
+	network.links[link] = append(network.links[link], Sent{Sender: sender, Receiver: receiver, Command: command, Value: value})
+	network.inFlight++
+}
+
+// Deliver hands out messages until none is left. The nodes may send while
+// they handle a message.
+func (network *Network) Deliver() {
+
+	for message, ok := network.next(); ok; message, ok = network.next() {
+
+		if message.Receiver == "server" {
+
+			// the relay takes the detour through the server
+			var relay = message.Value.(Relay)
+			network.SendMessage("server", relay.Receiver, relay.Command, relay.Value)
+			network.retarget(relay.Receiver, message.Sender)
+			continue
+		}
+
+		network.guard.Lock()
+		var node = network.nodes[message.Receiver]
+		network.guard.Unlock()
+
+		node.HandleMessage(message.Sender, message.Receiver, message.Command, message.Value)
+	}
+}
+
+// ServerMessages returns the messages sent to the server so far.
+func (network *Network) ServerMessages() []Sent {
+
+	network.guard.Lock()
+	defer network.guard.Unlock()
+	return append([]Sent{}, network.server...)
+}
+
+// The following helpers lock the guard themselves.
+
+// next removes the first message of a random link with pending messages.
+func (network *Network) next() (Sent, bool) {
+
+	network.guard.Lock()
+	defer network.guard.Unlock()
+
+	var links = [][2]string{}
+	for link, messages := range network.links {
+
+		if len(messages) > 0 {
+
+			links = append(links, link)
+		}
+	}
+
+	if len(links) == 0 {
+
+		return Sent{}, false
+	}
+
+	// map order is random on its own, sort for a reproducible choice
+	sort.Slice(links, func(i int, j int) bool {
+
+		return links[i][0] < links[j][0] || links[i][0] == links[j][0] && links[i][1] < links[j][1]
+	})
+	var link = links[network.random.Intn(len(links))]
+	var message = network.links[link][0]
+	network.links[link] = network.links[link][1:]
+	network.inFlight--
+	return message, true
+}
+
+// retarget names the original sender in the relayed message, as the server does.
+func (network *Network) retarget(receiver string, sender string) {
+
+	network.guard.Lock()
+	var messages = network.links[[2]string{"server", receiver}]
+	messages[len(messages)-1].Sender = sender
+	network.guard.Unlock()
+}
+
+//==============--------------------------------------------==============//
+//==============---------------- topologies ----------------==============//
+//==============--------------------------------------------==============//
+
+// IDOf returns the node ID of a vertex, the IDs sort like the vertices.
+func IDOf(vertex Vertex) string {
+
+	return Sprintf("v%02d", vertex)
+}
+
+// EnvironmentOf is what the client of the vertex passes to Init.
+func EnvironmentOf(topology *Graph, vertex Vertex, host algorithm.Host) algorithm.Environment {
+
+	var environment = algorithm.Environment{Host: host, ID: IDOf(vertex), Weights: map[string]float64{}}
+	for _, neighbor := range topology.Neighbors(vertex) {
+
+		var weight, _ = topology.WeightOf(vertex, neighbor)
+		environment.Neighbors = append(environment.Neighbors, IDOf(neighbor))
+		environment.Weights[IDOf(neighbor)] = float64(weight)
+	}
+	return environment
+}
+
+// NodesOf creates, initializes and attaches a node for every vertex.
+func NodesOf(topology *Graph, network *Network, factory algorithm.Factory) map[Vertex]algorithm.Algorithm {
+
+	var nodes = make(map[Vertex]algorithm.Algorithm)
+	for _, vertex := range topology.Vertices() {
+
+		nodes[vertex] = factory()
+		nodes[vertex].Init(EnvironmentOf(topology, vertex, network))
+		network.Attach(IDOf(vertex), nodes[vertex])
+	}
+	return nodes
+}
+
+// GraphOf creates a graph of {from, to, weight} edges.
+func GraphOf(edges [][3]int) *Graph {
+
+	var topology = NewGraph()
+	for _, edge := range edges {
+
+		topology.AddEdge(Edge{From: Vertex(edge[0]), To: Vertex(edge[1]), Weight: Weight(edge[2])})
+	}
+	return topology
+}
+
+// UnweightedGraphOf creates a graph of {from, to} edges of weight 1.
+func UnweightedGraphOf(edges [][2]int) *Graph {
+
+	var topology = NewGraph()
+	for _, edge := range edges {
+
+		topology.AddEdge(Edge{From: Vertex(edge[0]), To: Vertex(edge[1]), Weight: 1})
+	}
+	return topology
+}
+
+// RandomGraph creates a reproducible connected graph, a random tree plus
+// random edges, with weights in [1, maxWeight].
+func RandomGraph(vertices int, extraEdges int, maxWeight int, seed int64) *Graph {
+
+	var random = rand.New(rand.NewSource(seed))
+	var topology = NewGraph()
+	topology.AddVertex(0)
+	for vertex := 1; vertex < vertices; vertex++ {
+
+		topology.AddEdge(Edge{From: Vertex(random.Intn(vertex)), To: Vertex(vertex), Weight: Weight(random.Intn(maxWeight) + 1)})
+	}
+
+	for added := 0; added < extraEdges; {
+
+		var from, to = Vertex(random.Intn(vertices)), Vertex(random.Intn(vertices))
+		if from != to && !topology.HasEdge(from, to) {
+
+			topology.AddEdge(Edge{From: from, To: to, Weight: Weight(random.Intn(maxWeight) + 1)})
+			added++
+		}
+	}
+	return topology
+}
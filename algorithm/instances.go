@@ -0,0 +1,227 @@
+//
+//  instances.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+package algorithm
+
+import "sync"
+import "encoding/gob"
+
+// InstanceHost delivers messages that belong to a traversal instance.
+type InstanceHost interface {
+	SendInstanceMessage(instance string, sender string, receiver string, command uint8, value interface{})
+}
+
+// Instances runs independent copies of one algorithm on the same node, one per
+// traversal instance ID, so several runs can share the overlay without
+// interfering. The empty instance ID is the default instance, it is created
+// right away; all others are created by their first message.
+type Instances struct {
+	guard       sync.Mutex
+	name        string
+	environment Environment
+	host        InstanceHost
+	algorithms  map[string]Algorithm
+	mailboxes   map[string]*Mailbox
+	order       []string
+}
+
+// MessageHandler is anything that handles messages, e.g. an Algorithm.
+type MessageHandler interface {
+	HandleMessage(sender string, receiver string, command uint8, value interface{})
+}
+
+// Mailbox delivers messages to a handler sequentially and in the order they
+// were posted without ever blocking the sender, the handler may send while a
+// delivery is pending. Instances keeps one per instance.
+type Mailbox struct {
+	guard    sync.Mutex
+	handler  MessageHandler
+	messages []delivery
+	draining bool
+}
+
+type delivery struct {
+	sender   string
+	receiver string
+	command  uint8
+	value    interface{}
+}
+
+// InstanceResult is the result of one instance.
+type InstanceResult struct {
+	Instance string
+	Result   interface{}
+}
+
+// InstanceStatus is the state of one instance while it runs.
+type InstanceStatus struct {
+	Instance string
+	Status   interface{} // nil if the algorithm is no StatusReporter
+	Pending  int         // messages waiting in the mailbox of the instance
+}
+
+// instanceHost stamps every message of an algorithm with its instance ID.
+type instanceHost struct {
+	instance string
+	host     InstanceHost
+}
+
+func init() {
+
+	gob.Register([]InstanceStatus{})
+}
+
+func InstancesOf(name string, environment Environment, host InstanceHost) (*Instances, error) {
+
+	var instances = new(Instances)
+	instances.name = name
+	instances.environment = environment
+	instances.host = host
+	instances.algorithms = make(map[string]Algorithm)
+	instances.mailboxes = make(map[string]*Mailbox)
+
+	var _, algorithmError = instances.Instance("")
+	return instances, algorithmError
+}
+
+// Instance returns the algorithm of the instance and creates it if needed.
+func (instances *Instances) Instance(instance string) (Algorithm, error) {
+
+	instances.guard.Lock()
+	defer instances.guard.Unlock()
+
+	if existing, exists := instances.algorithms[instance]; exists {
+
+		return existing, nil
+	}
+
+	var newAlgorithm, algorithmError = New(instances.name)
+	if algorithmError != nil {
+
+		return nil, algorithmError
+	}
+
+	var environment = instances.environment
+	environment.Host = instanceHost{instance: instance, host: instances.host}
+	newAlgorithm.Init(environment)
+
+	instances.algorithms[instance] = newAlgorithm
+	instances.mailboxes[instance] = MailboxFor(newAlgorithm)
+	instances.order = append(instances.order, instance)
+	return newAlgorithm, nil
+}
+
+// HandleMessage queues the message for the instance and returns immediately.
+func (instances *Instances) HandleMessage(instance string, sender string, receiver string, command uint8, value interface{}) {
+
+	// the factory was validated when the default instance was created
+	instances.Instance(instance)
+
+	instances.guard.Lock()
+	var target = instances.mailboxes[instance]
+	instances.guard.Unlock()
+
+	target.Post(sender, receiver, command, value)
+}
+
+// Results returns the result of every instance in creation order.
+func (instances *Instances) Results() []InstanceResult {
+
+	instances.guard.Lock()
+	var order = append([]string{}, instances.order...)
+	var algorithms = make([]Algorithm, 0, len(order))
+	for _, instance := range order {
+
+		algorithms = append(algorithms, instances.algorithms[instance])
+	}
+	instances.guard.Unlock()
+
+	var results = make([]InstanceResult, 0, len(order))
+	for index, instance := range order {
+
+		results = append(results, InstanceResult{Instance: instance, Result: algorithms[index].Result()})
+	}
+	return results
+}
+
+// Statuses returns the status of every instance in creation order.
+func (instances *Instances) Statuses() []InstanceStatus {
+
+	instances.guard.Lock()
+	var order = append([]string{}, instances.order...)
+	var boxes = make([]*Mailbox, 0, len(order))
+	for _, instance := range order {
+
+		boxes = append(boxes, instances.mailboxes[instance])
+	}
+	instances.guard.Unlock()
+
+	var statuses = make([]InstanceStatus, 0, len(order))
+	for index, instance := range order {
+
+		var status = InstanceStatus{Instance: instance, Pending: boxes[index].Pending()}
+		if reporter, ok := boxes[index].handler.(StatusReporter); ok {
+
+			status.Status = reporter.Status()
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+func MailboxFor(handler MessageHandler) *Mailbox {
+
+	return &Mailbox{handler: handler}
+}
+
+// Pending returns the number of messages waiting for delivery.
+func (box *Mailbox) Pending() int {
+
+	box.guard.Lock()
+	defer box.guard.Unlock()
+	return len(box.messages)
+}
+
+// Post queues the message for the handler and returns immediately.
+func (box *Mailbox) Post(sender string, receiver string, command uint8, value interface{}) {
+
+	box.guard.Lock()
+	box.messages = append(box.messages, delivery{sender: sender, receiver: receiver, command: command, value: value})
+	var start = !box.draining
+	box.draining = true
+	box.guard.Unlock()
+
+	if start {
+
+		go box.drain()
+	}
+}
+
+func (box *Mailbox) drain() {
+
+	for {
+
+		box.guard.Lock()
+		if len(box.messages) == 0 {
+
+			box.draining = false
+			box.guard.Unlock()
+			return
+		}
+		var message = box.messages[0]
+		box.messages = box.messages[1:]
+		box.guard.Unlock()
+
+		box.handler.HandleMessage(message.sender, message.receiver, message.command, message.value)
+	}
+}
+
+func (host instanceHost) SendMessage(sender string, receiver string, command uint8, value interface{}) {
+
+	host.host.SendInstanceMessage(host.instance, sender, receiver, command, value)
+}
@@ -0,0 +1,279 @@
+//
+//  instances_test.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+package algorithm
+
+import "errors"
+import "reflect"
+import "sync"
+import "testing"
+import "time"
+
+// recorder remembers the values it received and answers command 1 with
+// command 2 to the sender.
+type recorder struct {
+	guard    sync.Mutex
+	host     Host
+	id       string
+	values   []interface{}
+	handling bool
+	overlaps int // deliveries that started while another one ran
+}
+
+// sentMessage is a message an instance handed to its host.
+type sentMessage struct {
+	instance string
+	sender   string
+	receiver string
+	command  uint8
+	value    interface{}
+}
+
+type fakeHost struct {
+	guard sync.Mutex
+	sent  []sentMessage
+}
+
+func init() {
+
+	Register("recorder", func() Algorithm { return new(recorder) })
+}
+
+func (algorithm *recorder) Init(environment Environment) {
+
+	algorithm.host = environment.Host
+	algorithm.id = environment.ID
+}
+
+func (algorithm *recorder) HandleMessage(sender string, receiver string, command uint8, value interface{}) {
+
+	algorithm.guard.Lock()
+	if algorithm.handling {
+
+		algorithm.overlaps++
+	}
+	algorithm.handling = true
+	algorithm.values = append(algorithm.values, value)
+	algorithm.guard.Unlock()
+
+	if command == 1 {
+
+		algorithm.host.SendMessage(algorithm.id, sender, 2, value)
+	}
+
+	algorithm.guard.Lock()
+	algorithm.handling = false
+	algorithm.guard.Unlock()
+}
+
+func (algorithm *recorder) Result() interface{} {
+
+	algorithm.guard.Lock()
+	defer algorithm.guard.Unlock()
+	return append([]interface{}{}, algorithm.values...)
+}
+
+func (algorithm *recorder) Status() interface{} {
+
+	algorithm.guard.Lock()
+	defer algorithm.guard.Unlock()
+	return len(algorithm.values)
+}
+
+func (host *fakeHost) SendInstanceMessage(instance string, sender string, receiver string, command uint8, value interface{}) {
+
+	host.guard.Lock()
+	host.sent = append(host.sent, sentMessage{instance, sender, receiver, command, value})
+	host.guard.Unlock()
+}
+
+// eventually waits up to a second for condition to hold.
+func eventually(condition func() bool) bool {
+
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+
+		if condition() {
+
+			return true
+		}
+	}
+	return condition()
+}
+
+func TestMailboxDeliversSequentiallyInOrder(t *testing.T) {
+
+	var handler = new(recorder)
+	var box = MailboxFor(handler)
+	var expected = []interface{}{}
+
+	// the sender is never blocked by a slow handler, so posting races the drain
+	for value := 0; value < 1000; value++ {
+
+		box.Post("a", "b", 0, value)
+		expected = append(expected, value)
+	}
+
+	if !eventually(func() bool { return len(handler.Result().([]interface{})) == len(expected) }) {
+
+		t.Fatalf("handled %d of %d messages", len(handler.Result().([]interface{})), len(expected))
+	}
+
+	if values := handler.Result(); !reflect.DeepEqual(values, expected) {
+
+		t.Errorf("handled the messages out of order: %v", values)
+	}
+
+	if handler.overlaps != 0 || box.Pending() != 0 {
+
+		t.Errorf("%d deliveries overlapped and %d are pending, expected none", handler.overlaps, box.Pending())
+	}
+}
+
+// poster posts the next number to its own mailbox while it handles one.
+type poster struct {
+	box    *Mailbox
+	values []int
+	done   chan bool
+}
+
+func (handler *poster) HandleMessage(sender string, receiver string, command uint8, value interface{}) {
+
+	handler.values = append(handler.values, value.(int))
+	if value.(int) < 100 {
+
+		handler.box.Post(sender, receiver, command, value.(int)+1)
+
+	} else {
+
+		close(handler.done)
+	}
+}
+
+func TestMailboxHandlerMayPostToItsOwnMailbox(t *testing.T) {
+
+	var handler = &poster{done: make(chan bool)}
+	handler.box = MailboxFor(handler)
+	handler.box.Post("a", "a", 0, 0)
+
+	select {
+	case <-handler.done:
+	case <-time.After(time.Second):
+		t.Fatalf("the mailbox stalled after %d messages", len(handler.values))
+	}
+
+	for index, value := range handler.values {
+
+		if value != index {
+
+			t.Fatalf("handled %v, expected 0 to 100 in order", handler.values)
+		}
+	}
+}
+
+func TestInstancesAreCreatedByTheirFirstMessage(t *testing.T) {
+
+	var host = new(fakeHost)
+	var instances, instancesError = InstancesOf("recorder", Environment{ID: "a"}, host)
+	if instancesError != nil {
+
+		t.Fatalf("unexpected error %v", instancesError)
+	}
+
+	var instanceIDs = func() []string {
+
+		var ids = []string{}
+		for _, result := range instances.Results() {
+
+			ids = append(ids, result.Instance)
+		}
+		return ids
+	}
+
+	if ids := instanceIDs(); !reflect.DeepEqual(ids, []string{""}) {
+
+		t.Fatalf("got instances %q before any message, expected only the default one", ids)
+	}
+
+	var messages = []struct {
+		instance string
+		command  uint8
+		value    string
+	}{
+		{"x", 0, "x1"},
+		{"", 1, "default"},
+		{"y", 1, "y1"},
+		{"x", 1, "x2"},
+	}
+
+	for _, message := range messages {
+
+		instances.HandleMessage(message.instance, "b", "a", message.command, message.value)
+	}
+
+	if ids := instanceIDs(); !reflect.DeepEqual(ids, []string{"", "x", "y"}) {
+
+		t.Errorf("got instances %q, expected them in creation order [\"\" x y]", ids)
+	}
+
+	var expected = map[string][]interface{}{"": {"default"}, "x": {"x1", "x2"}, "y": {"y1"}}
+	var delivered = func() bool {
+
+		host.guard.Lock()
+		var answers = len(host.sent)
+		host.guard.Unlock()
+
+		if answers != 3 {
+
+			return false
+		}
+
+		for _, result := range instances.Results() {
+
+			if !reflect.DeepEqual(result.Result, expected[result.Instance]) {
+
+				return false
+			}
+		}
+		return true
+	}
+
+	if !eventually(delivered) {
+
+		t.Errorf("got results %v, expected %v", instances.Results(), expected)
+	}
+
+	// every answer is stamped with the instance that sent it
+	host.guard.Lock()
+	var sent = map[string]string{}
+	for _, message := range host.sent {
+
+		sent[message.value.(string)] = message.instance
+	}
+	host.guard.Unlock()
+
+	if !reflect.DeepEqual(sent, map[string]string{"default": "", "y1": "y", "x2": "x"}) {
+
+		t.Errorf("sent %v, expected every answer stamped with its instance", sent)
+	}
+
+	for _, status := range instances.Statuses() {
+
+		if status.Status != len(expected[status.Instance]) || status.Pending != 0 {
+
+			t.Errorf("instance %q has status %v with %d pending", status.Instance, status.Status, status.Pending)
+		}
+	}
+}
+
+func TestInstancesOfUnknownAlgorithm(t *testing.T) {
+
+	if _, err := InstancesOf("unknown", Environment{ID: "a"}, new(fakeHost)); !errors.Is(err, ErrUnknownAlgorithm) {
+
+		t.Errorf("got %v, expected %v", err, ErrUnknownAlgorithm)
+	}
+}
@@ -28,14 +28,31 @@
 // OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
 //
 
+// Package array provides a concurrency safe array with runtime checked element types.
 package array
 
 import "reflect"
 import "strings"
+import "sort"
+import "errors"
 import "sync"
 import "log"
 import "fmt"
 
+//==============--------------------------------------------==============//
+//==============------------------ errors ------------------==============//
+//==============--------------------------------------------==============//
+
+// The Try* variants of the array operations return one of these errors
+// (wrapped with more context) instead of terminating the process.
+var (
+	ErrTypeNotRegistered = errors.New("type is not registered in module array")
+	ErrTypeAlreadySet    = errors.New("array type is already set")
+	ErrTypeNotSet        = errors.New("array type is not set yet")
+	ErrTypeMismatch      = errors.New("element type does not match array type")
+	ErrIndexOutOfRange   = errors.New("index out of range")
+)
+
 //==============--------------------------------------------==============//
 //==============------- registry type container/guard ------==============//
 //==============--------------------------------------------==============//
@@ -43,6 +60,21 @@ var types = []string{"bool", "uint8", "complex128", "complex64", "float32", "flo
 	"int32", "int64", "int8", "int32", "string", "uint", "uint16", "uint32", "uint64", "uint8"}
 var typeGuard = new(sync.Mutex)
 
+// reflectTypes maps registered type names to their type, which is needed to
+// decode elements of serialized arrays.
+var reflectTypes = map[string]reflect.Type{}
+
+func init() {
+
+	var builtinValues = []interface{}{false, uint8(0), complex128(0), complex64(0), float32(0), float64(0), int(0),
+		int16(0), int32(0), int64(0), int8(0), "", uint(0), uint16(0), uint32(0), uint64(0)}
+
+	for _, value := range builtinValues {
+
+		reflectTypes[GetTypeName(value)] = reflect.TypeOf(value)
+	}
+}
+
 //==============--------------------------------------------==============//
 //==============--------- type registry functions ----------==============//
 //==============--------------------------------------------==============//
@@ -88,6 +120,7 @@ func RegisterType(value interface{}) {
 
 		typeGuard.Lock()
 		types = append(types, typeName)
+		reflectTypes[typeName] = reflect.TypeOf(value)
 		typeGuard.Unlock()
 	}
 }
@@ -117,41 +150,67 @@ func ArrayOfType(typeName string) *Array {
 	return array.SetType(typeName)
 }
 
+func TryArrayOfType(typeName string) (*Array, error) {
+
+	var array = new(Array)
+	return array, array.TrySetType(typeName)
+}
+
 //==============--------------------------------------------==============//
 //==============--------- array type getter/setter ---------==============//
 //==============--------------------------------------------==============//
 func (array *Array) SetType(typeName string) *Array {
 
+	var setError = array.TrySetType(typeName)
+	if errors.Is(setError, ErrTypeNotRegistered) {
+
+		log.Fatalf("FATAL: %v\nUSAGE: RegisterType(instanceOfNotRegisteredType)\n", setError)
+
+	} else if setError != nil {
+
+		log.Fatalf("FATAL: %v\n", setError)
+	}
+	return array
+}
+
+func (array *Array) TrySetType(typeName string) error {
+
 	array.guard.Lock()
-	if equalTypes(array.elementType, "") {
+	defer array.guard.Unlock()
 
-		if !IsTypeRegistered(typeName) {
+	if !equalTypes(array.elementType, "") {
 
-			array.guard.Unlock() // unlock before throw
-			log.Fatalf("FATAL: type <%s> is not registered in module array yet\nUSAGE: RegisterType(instanceOfNotRegisteredType)\n", typeName)
-		}
-		array.elementType = typeName
-		array.guard.Unlock()
+		return fmt.Errorf("%w: array <%p> has type <%s>", ErrTypeAlreadySet, array, array.elementType)
+	}
 
-	} else {
+	if !IsTypeRegistered(typeName) {
 
-		array.guard.Unlock() // unlock before throw
-		log.Fatalf("FATAL: type of array <%p> is already set to <%s>\n", array, array.elementType)
+		return fmt.Errorf("%w: <%s>", ErrTypeNotRegistered, typeName)
 	}
-	return array
+	array.elementType = typeName
+	return nil
 }
 
 func (array *Array) Type() string {
 
+	var elementType, typeError = array.TryType()
+	if typeError != nil {
+
+		log.Fatalf("FATAL: %v\n", typeError)
+	}
+	return elementType
+}
+
+func (array *Array) TryType() (string, error) {
+
 	array.guard.Lock()
+	defer array.guard.Unlock()
+
 	if equalTypes(array.elementType, "") {
 
-		array.guard.Unlock() // unlock before throw
-		log.Fatalf("FATAL: type for array <%p> is not set yet\n", array)
+		return "", fmt.Errorf("%w: array <%p>", ErrTypeNotSet, array)
 	}
-	var elementType = array.elementType
-	array.guard.Unlock()
-	return elementType
+	return array.elementType, nil
 }
 
 //==============--------------------------------------------==============//
@@ -160,15 +219,43 @@ func (array *Array) Type() string {
 func (array *Array) Append(newElement Element) {
 
 	array.typeCheck(newElement)
+	array.append(newElement)
+}
 
-	array.guard.Lock()
-	array.elements = append(array.elements, newElement)
-	array.guard.Unlock()
+func (array *Array) TryAppend(newElement Element) error {
+
+	if checkError := array.checkType(newElement); checkError != nil {
+
+		return checkError
+	}
+	array.append(newElement)
+	return nil
 }
 
 func (array *Array) InsertAtIndex(newElement Element, index int) {
 
 	array.typeCheck(newElement)
+	array.insertAtIndex(newElement, index)
+}
+
+func (array *Array) TryInsertAtIndex(newElement Element, index int) error {
+
+	if checkError := array.checkType(newElement); checkError != nil {
+
+		return checkError
+	}
+	array.insertAtIndex(newElement, index)
+	return nil
+}
+
+func (array *Array) append(newElement Element) {
+
+	array.guard.Lock()
+	array.elements = append(array.elements, newElement)
+	array.guard.Unlock()
+}
+
+func (array *Array) insertAtIndex(newElement Element, index int) {
 
 	array.guard.Lock()
 	var anIndex = 0
@@ -214,6 +301,20 @@ func (array *Array) RemoveAtIndex(index int) Element {
 func (array *Array) Remove(element Element) {
 
 	array.typeCheck(element)
+	array.remove(element)
+}
+
+func (array *Array) TryRemove(element Element) error {
+
+	if checkError := array.checkType(element); checkError != nil {
+
+		return checkError
+	}
+	array.remove(element)
+	return nil
+}
+
+func (array *Array) remove(element Element) {
 
 	array.guard.Lock()
 	var index = -1
@@ -272,24 +373,31 @@ func (array *Array) IsEmpty() bool {
 //==============--------------------------------------------==============//
 func (array *Array) Contains(element Element) bool {
 
-	array.typeCheck(element)
-
-	array.guard.Lock()
-	for _, anElement := range array.elements {
+	return array.IndexOf(element) >= 0
+}
 
-		if reflect.DeepEqual(anElement, element) {
+func (array *Array) TryContains(element Element) (bool, error) {
 
-			array.guard.Unlock()
-			return true
-		}
-	}
-	array.guard.Unlock()
-	return false
+	var index, indexError = array.TryIndexOf(element)
+	return index >= 0, indexError
 }
 
 func (array *Array) IndexOf(element Element) int {
 
 	array.typeCheck(element)
+	return array.indexOf(element)
+}
+
+func (array *Array) TryIndexOf(element Element) (int, error) {
+
+	if checkError := array.checkType(element); checkError != nil {
+
+		return -1, checkError
+	}
+	return array.indexOf(element), nil
+}
+
+func (array *Array) indexOf(element Element) int {
 
 	array.guard.Lock()
 	for index, anElement := range array.elements {
@@ -340,17 +448,129 @@ func (array *Array) Last() Element {
 //==============--------------------------------------------==============//
 func (array *Array) SetAtIndex(element Element, index int) {
 
+	if setError := array.TrySetAtIndex(element, index); setError != nil {
+
+		log.Fatalf("FATAL: %v\n", setError)
+	}
+}
+
+func (array *Array) TrySetAtIndex(element Element, index int) error {
+
+	if checkError := array.checkType(element); checkError != nil {
+
+		return checkError
+	}
+
 	array.guard.Lock()
+	defer array.guard.Unlock()
 
 	if index < 0 || index >= len(array.elements) {
 
-		array.guard.Unlock()
-		log.Fatalf("FATAL: index %d out of range for array <%p> with elements: %v\n", index, array, array.elements)
+		return fmt.Errorf("%w: index %d for array <%p> with elements: %v", ErrIndexOutOfRange, index, array, array.elements)
 	}
-
 	array.elements[index] = element
+	return nil
+}
 
+//==============--------------------------------------------==============//
+//==============----------- iteration/functional -----------==============//
+//==============--------------------------------------------==============//
+
+// Snapshot copies the elements under a single lock acquisition, so the
+// result is a consistent view even if other goroutines modify the array.
+func (array *Array) Snapshot() []Element {
+
+	array.guard.Lock()
+	var elements = append([]Element{}, array.elements...)
 	array.guard.Unlock()
+
+	return elements
+}
+
+// Each calls body for every element of a snapshot until body returns false.
+// The array is not locked while body runs, so body may modify it.
+func (array *Array) Each(body func(index int, element Element) bool) {
+
+	for index, element := range array.Snapshot() {
+
+		if !body(index, element) {
+			break
+		}
+	}
+}
+
+func (array *Array) Filter(isIncluded func(element Element) bool) *Array {
+
+	var filtered = ArrayOfType(array.Type())
+	for _, element := range array.Snapshot() {
+
+		if isIncluded(element) {
+
+			filtered.elements = append(filtered.elements, element)
+		}
+	}
+	return filtered
+}
+
+// Map creates an array of type typeName from the transformed elements.
+func (array *Array) Map(typeName string, transform func(element Element) Element) *Array {
+
+	var mapped = ArrayOfType(typeName)
+	for _, element := range array.Snapshot() {
+
+		mapped.Append(transform(element))
+	}
+	return mapped
+}
+
+// Find returns the first element matching predicate or nil.
+func (array *Array) Find(predicate func(element Element) bool) Element {
+
+	for _, element := range array.Snapshot() {
+
+		if predicate(element) {
+
+			return element
+		}
+	}
+	return nil
+}
+
+// SortBy sorts the elements in place, keeping the order of equal elements.
+func (array *Array) SortBy(isOrderedBefore func(lhs Element, rhs Element) bool) {
+
+	array.guard.Lock()
+	sort.SliceStable(array.elements, func(i int, j int) bool {
+
+		return isOrderedBefore(array.elements[i], array.elements[j])
+	})
+	array.guard.Unlock()
+}
+
+// ToSlice stores a snapshot of the elements into the slice slicePointer
+// points to, e.g. ToSlice(&ids) with ids of type []string.
+func (array *Array) ToSlice(slicePointer interface{}) error {
+
+	var target = reflect.ValueOf(slicePointer)
+	if target.Kind() != reflect.Ptr || target.Elem().Kind() != reflect.Slice {
+
+		return fmt.Errorf("%w: expected a pointer to a slice, got <%T>", ErrTypeMismatch, slicePointer)
+	}
+
+	var slice = reflect.MakeSlice(target.Elem().Type(), 0, array.Count())
+	var elementType = target.Elem().Type().Elem()
+
+	for _, element := range array.Snapshot() {
+
+		var value = reflect.ValueOf(element)
+		if !value.Type().AssignableTo(elementType) {
+
+			return fmt.Errorf("%w: element of type <%s> can not be stored in <%s>", ErrTypeMismatch, value.Type(), target.Elem().Type())
+		}
+		slice = reflect.Append(slice, value)
+	}
+	target.Elem().Set(slice)
+	return nil
 }
 
 //==============--------------------------------------------==============//
@@ -371,20 +591,28 @@ func equalTypes(type1 string, type2 string) bool {
 
 func (array *Array) typeCheck(element Element) {
 
+	if checkError := array.checkType(element); checkError != nil {
+
+		log.Fatalf("FATAL: %v\n", checkError)
+	}
+}
+
+func (array *Array) checkType(element Element) error {
+
 	var elementType = GetTypeName(element)
 
 	array.guard.Lock()
+	defer array.guard.Unlock()
+
 	if equalTypes(array.elementType, "") {
 
-		array.guard.Unlock() // unlock before throw
-		log.Fatalf("FATAL: type for array <%p> is not set yet\n", array)
+		return fmt.Errorf("%w: array <%p>", ErrTypeNotSet, array)
 
 	} else if !equalTypes(elementType, array.elementType) {
 
-		array.guard.Unlock() // unlock before throw
-		log.Fatalf("FATAL: array <%p> of type <%s> can not procced with an element of type <%s>\n", array, array.elementType, elementType)
+		return fmt.Errorf("%w: array <%p> of type <%s> can not procced with an element of type <%s>", ErrTypeMismatch, array, array.elementType, elementType)
 	}
-	array.guard.Unlock()
+	return nil
 }
 
 //////////////
@@ -393,8 +621,13 @@ func (array *Array) typeCheck(element Element) {
 
 func (array *Array) Clone() *Array {
 
-	var newArray = ArrayOfType(array.elementType)
-	newArray.elements = append([]Element{}, array.elements...)
+	array.guard.Lock()
+	var elementType = array.elementType
+	var elements = append([]Element{}, array.elements...)
+	array.guard.Unlock()
+
+	var newArray = ArrayOfType(elementType)
+	newArray.elements = elements
 	return newArray
 }
 
@@ -405,3 +638,13 @@ func (array *Array) AppendUnique(newElement Element) {
 	}
 	array.Append(newElement)
 }
+
+func (array *Array) TryAppendUnique(newElement Element) error {
+
+	var contains, containsError = array.TryContains(newElement)
+	if containsError != nil || contains {
+
+		return containsError
+	}
+	return array.TryAppend(newElement)
+}
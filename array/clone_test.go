@@ -0,0 +1,145 @@
+//
+// Copyright (c) 2016, Adrian Zubarev (a.k.a. DevAndArtist)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// * Redistributions of source code must retain the above copyright notice, this
+//   list of conditions and the following disclaimer.
+//
+// * Redistributions in binary form must reproduce the above copyright notice,
+//   this list of conditions and the following disclaimer in the documentation
+//   and/or other materials provided with the distribution.
+//
+// * Neither the name of Go-Array nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package array
+
+import "reflect"
+import "testing"
+
+func TestOrderedSetCloneIsolation(t *testing.T) {
+
+	var tests = []struct {
+		name     string
+		modify   func(original *OrderedSet[int], clone *OrderedSet[int])
+		original []int
+		clone    []int
+	}{
+		{"insert into clone", func(original *OrderedSet[int], clone *OrderedSet[int]) { clone.Insert(4) }, []int{1, 2, 3}, []int{1, 2, 3, 4}},
+		{"insert into original", func(original *OrderedSet[int], clone *OrderedSet[int]) { original.Insert(4) }, []int{1, 2, 3, 4}, []int{1, 2, 3}},
+		{"remove from clone", func(original *OrderedSet[int], clone *OrderedSet[int]) { clone.Remove(2) }, []int{1, 2, 3}, []int{1, 3}},
+		{"remove from original", func(original *OrderedSet[int], clone *OrderedSet[int]) { original.Remove(1) }, []int{2, 3}, []int{1, 2, 3}},
+		{"remove all from clone", func(original *OrderedSet[int], clone *OrderedSet[int]) { clone.RemoveAll() }, []int{1, 2, 3}, []int{}},
+		{"modify both", func(original *OrderedSet[int], clone *OrderedSet[int]) {
+
+			original.Insert(4)
+			clone.Insert(5)
+			original.Remove(1)
+			clone.Remove(3)
+		}, []int{2, 3, 4}, []int{1, 2, 5}},
+		{"clone of a clone", func(original *OrderedSet[int], clone *OrderedSet[int]) {
+
+			var second = clone.Clone()
+			second.Insert(6)
+			clone.Insert(7)
+		}, []int{1, 2, 3}, []int{1, 2, 3, 7}},
+	}
+
+	for _, test := range tests {
+
+		var original = OrderedSetOf(1, 2, 3)
+		var clone = original.Clone()
+		test.modify(original, clone)
+
+		if elements := original.Elements(); !reflect.DeepEqual(elements, test.original) {
+
+			t.Errorf("%s: original has %v, expected %v", test.name, elements, test.original)
+		}
+
+		if elements := clone.Elements(); !reflect.DeepEqual(elements, test.clone) {
+
+			t.Errorf("%s: clone has %v, expected %v", test.name, elements, test.clone)
+		}
+	}
+}
+
+func TestOrderedSetCloneAfterRemovals(t *testing.T) {
+
+	// removed entries are kept as tombstones until compaction, clones must not see them
+	var original = OrderedSetOf(1, 2, 3, 4, 5)
+	original.Remove(2)
+
+	var clone = original.Clone()
+	clone.Insert(2)
+	original.Remove(4)
+
+	if elements := original.Elements(); !reflect.DeepEqual(elements, []int{1, 3, 5}) {
+
+		t.Errorf("original has %v, expected [1 3 5]", elements)
+	}
+
+	if elements := clone.Elements(); !reflect.DeepEqual(elements, []int{1, 3, 4, 5, 2}) {
+
+		t.Errorf("clone has %v, expected [1 3 4 5 2]", elements)
+	}
+}
+
+func TestArrayCloneIsolation(t *testing.T) {
+
+	var original = ArrayOfType("string")
+	original.Append("a")
+
+	var clone = original.Clone()
+	clone.Append("b")
+	original.SetAtIndex("c", 0)
+
+	if elements := original.Snapshot(); !reflect.DeepEqual(elements, []Element{"c"}) {
+
+		t.Errorf("original has %v, expected [c]", elements)
+	}
+
+	if elements := clone.Snapshot(); !reflect.DeepEqual(elements, []Element{"a", "b"}) {
+
+		t.Errorf("clone has %v, expected [a b]", elements)
+	}
+
+	if elementType := clone.Type(); elementType != "string" {
+
+		t.Errorf("clone has type %s, expected string", elementType)
+	}
+}
+
+func TestTypedArrayCloneIsolation(t *testing.T) {
+
+	var original = TypedArrayOf(1, 2)
+	var clone = original.Clone()
+
+	clone.Append(3)
+	original.SetAtIndex(9, 0)
+
+	if elements := original.ToSlice(); !reflect.DeepEqual(elements, []int{9, 2}) {
+
+		t.Errorf("original has %v, expected [9 2]", elements)
+	}
+
+	if elements := clone.ToSlice(); !reflect.DeepEqual(elements, []int{1, 2, 3}) {
+
+		t.Errorf("clone has %v, expected [1 2 3]", elements)
+	}
+}
@@ -0,0 +1,211 @@
+//
+// Copyright (c) 2016, Adrian Zubarev (a.k.a. DevAndArtist)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// * Redistributions of source code must retain the above copyright notice, this
+//   list of conditions and the following disclaimer.
+//
+// * Redistributions in binary form must reproduce the above copyright notice,
+//   this list of conditions and the following disclaimer in the documentation
+//   and/or other materials provided with the distribution.
+//
+// * Neither the name of Go-Array nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package array
+
+import "bytes"
+import "reflect"
+import "encoding/gob"
+import "encoding/json"
+import "fmt"
+
+func init() {
+
+	// arrays are sent as message values, which gob only decodes for registered types
+	gob.Register(new(Array))
+}
+
+//==============--------------------------------------------==============//
+//==============----------------- JSON ---------------------==============//
+//==============--------------------------------------------==============//
+
+// jsonArray is the wire format of an Array, e.g.
+// {"type":"string","elements":["a","b"]}.
+type jsonArray struct {
+	Type     string            `json:"type"`
+	Elements []json.RawMessage `json:"elements"`
+}
+
+func (array *Array) MarshalJSON() ([]byte, error) {
+
+	var elementType, typeError = array.TryType()
+	if typeError != nil {
+
+		return nil, typeError
+	}
+
+	var wire = jsonArray{Type: elementType, Elements: []json.RawMessage{}}
+	for _, element := range array.Snapshot() {
+
+		var data, encodingError = json.Marshal(element)
+		if encodingError != nil {
+
+			return nil, encodingError
+		}
+		wire.Elements = append(wire.Elements, data)
+	}
+	return json.Marshal(wire)
+}
+
+// UnmarshalJSON accepts an array without a type or with the same type as the
+// encoded one. The decoded elements replace the current ones.
+func (array *Array) UnmarshalJSON(data []byte) error {
+
+	var wire jsonArray
+	if decodingError := json.Unmarshal(data, &wire); decodingError != nil {
+
+		return decodingError
+	}
+
+	var valueType, lookupError = array.prepareDecoding(wire.Type)
+	if lookupError != nil {
+
+		return lookupError
+	}
+
+	var elements = []Element{}
+	for _, rawElement := range wire.Elements {
+
+		var value = reflect.New(valueType)
+		if decodingError := json.Unmarshal(rawElement, value.Interface()); decodingError != nil {
+
+			return decodingError
+		}
+		elements = append(elements, value.Elem().Interface())
+	}
+	array.replaceElements(wire.Type, elements)
+	return nil
+}
+
+//==============--------------------------------------------==============//
+//==============------------------ gob ---------------------==============//
+//==============--------------------------------------------==============//
+
+// GobEncode writes the type name, the element count and then every element.
+func (array *Array) GobEncode() ([]byte, error) {
+
+	var elementType, typeError = array.TryType()
+	if typeError != nil {
+
+		return nil, typeError
+	}
+
+	var elements = array.Snapshot()
+	var buffer bytes.Buffer
+	var encoder = gob.NewEncoder(&buffer)
+
+	if encodingError := encoder.Encode(elementType); encodingError != nil {
+
+		return nil, encodingError
+	}
+
+	if encodingError := encoder.Encode(len(elements)); encodingError != nil {
+
+		return nil, encodingError
+	}
+
+	for _, element := range elements {
+
+		if encodingError := encoder.EncodeValue(reflect.ValueOf(element)); encodingError != nil {
+
+			return nil, encodingError
+		}
+	}
+	return buffer.Bytes(), nil
+}
+
+// GobDecode has the same type rules as UnmarshalJSON.
+func (array *Array) GobDecode(data []byte) error {
+
+	var decoder = gob.NewDecoder(bytes.NewReader(data))
+	var elementType string
+	var count int
+
+	if decodingError := decoder.Decode(&elementType); decodingError != nil {
+
+		return decodingError
+	}
+
+	if decodingError := decoder.Decode(&count); decodingError != nil {
+
+		return decodingError
+	}
+
+	var valueType, lookupError = array.prepareDecoding(elementType)
+	if lookupError != nil {
+
+		return lookupError
+	}
+
+	var elements = make([]Element, 0, count)
+	for i := 0; i < count; i++ {
+
+		var value = reflect.New(valueType)
+		if decodingError := decoder.DecodeValue(value); decodingError != nil {
+
+			return decodingError
+		}
+		elements = append(elements, value.Elem().Interface())
+	}
+	array.replaceElements(elementType, elements)
+	return nil
+}
+
+// ==============--------------------------------------------==============//
+// ==============-------------- private helper --------------==============//
+// ==============--------------------------------------------==============//
+func (array *Array) prepareDecoding(elementType string) (reflect.Type, error) {
+
+	typeGuard.Lock()
+	var valueType, registered = reflectTypes[elementType]
+	typeGuard.Unlock()
+
+	if !registered {
+
+		return nil, fmt.Errorf("%w: <%s>", ErrTypeNotRegistered, elementType)
+	}
+
+	array.guard.Lock()
+	defer array.guard.Unlock()
+
+	if !equalTypes(array.elementType, "") && !equalTypes(array.elementType, elementType) {
+
+		return nil, fmt.Errorf("%w: array <%p> of type <%s> can not decode elements of type <%s>", ErrTypeMismatch, array, array.elementType, elementType)
+	}
+	return valueType, nil
+}
+
+func (array *Array) replaceElements(elementType string, elements []Element) {
+
+	array.guard.Lock()
+	array.elementType = elementType
+	array.elements = elements
+	array.guard.Unlock()
+}
@@ -0,0 +1,177 @@
+//
+// Copyright (c) 2016, Adrian Zubarev (a.k.a. DevAndArtist)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// * Redistributions of source code must retain the above copyright notice, this
+//   list of conditions and the following disclaimer.
+//
+// * Redistributions in binary form must reproduce the above copyright notice,
+//   this list of conditions and the following disclaimer in the documentation
+//   and/or other materials provided with the distribution.
+//
+// * Neither the name of Go-Array nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package array
+
+import "bytes"
+import "encoding/gob"
+import "encoding/json"
+import "errors"
+import "reflect"
+import "testing"
+
+type encodingPoint struct {
+	X int
+	Y int
+}
+
+func init() {
+
+	RegisterType(encodingPoint{})
+}
+
+func arrayWith(typeName string, elements ...Element) *Array {
+
+	var array = ArrayOfType(typeName)
+	for _, element := range elements {
+
+		array.Append(element)
+	}
+	return array
+}
+
+// roundTripArrays is a function, because package variables are initialized
+// before init registers encodingPoint.
+func roundTripArrays() []struct {
+	name  string
+	array *Array
+} {
+
+	return []struct {
+		name  string
+		array *Array
+	}{
+		{"strings", arrayWith("string", "a", "b")},
+		{"ints", arrayWith("int", 1, -2, 3)},
+		{"floats", arrayWith("float64", 1.5, -0.25)},
+		{"bools", arrayWith("bool", true, false)},
+		{"empty", arrayWith("uint64")},
+		{"registered struct", arrayWith("encodingPoint", encodingPoint{1, 2}, encodingPoint{3, 4})},
+	}
+}
+
+func TestArrayJSONRoundTrip(t *testing.T) {
+
+	for _, test := range roundTripArrays() {
+
+		var data, encodingError = json.Marshal(test.array)
+		if encodingError != nil {
+
+			t.Errorf("%s: encoding failed: %v", test.name, encodingError)
+			continue
+		}
+
+		var decoded = new(Array)
+		if decodingError := json.Unmarshal(data, decoded); decodingError != nil {
+
+			t.Errorf("%s: decoding %s failed: %v", test.name, data, decodingError)
+			continue
+		}
+
+		if decoded.Type() != test.array.Type() || !reflect.DeepEqual(decoded.Snapshot(), test.array.Snapshot()) {
+
+			t.Errorf("%s: decoded %v, expected %v", test.name, decoded, test.array)
+		}
+	}
+}
+
+func TestArrayGobRoundTrip(t *testing.T) {
+
+	for _, test := range roundTripArrays() {
+
+		var buffer bytes.Buffer
+		if encodingError := gob.NewEncoder(&buffer).Encode(test.array); encodingError != nil {
+
+			t.Errorf("%s: encoding failed: %v", test.name, encodingError)
+			continue
+		}
+
+		var decoded = new(Array)
+		if decodingError := gob.NewDecoder(&buffer).Decode(decoded); decodingError != nil {
+
+			t.Errorf("%s: decoding failed: %v", test.name, decodingError)
+			continue
+		}
+
+		if decoded.Type() != test.array.Type() || !reflect.DeepEqual(decoded.Snapshot(), test.array.Snapshot()) {
+
+			t.Errorf("%s: decoded %v, expected %v", test.name, decoded, test.array)
+		}
+	}
+}
+
+func TestArrayGobRoundTripAsInterface(t *testing.T) {
+
+	// message values are interfaces, which is why the package registers *Array with gob
+	var value interface{} = arrayWith("string", "a")
+
+	var buffer bytes.Buffer
+	if encodingError := gob.NewEncoder(&buffer).Encode(&value); encodingError != nil {
+
+		t.Fatalf("encoding failed: %v", encodingError)
+	}
+
+	var decoded interface{}
+	if decodingError := gob.NewDecoder(&buffer).Decode(&decoded); decodingError != nil {
+
+		t.Fatalf("decoding failed: %v", decodingError)
+	}
+
+	if array, isArray := decoded.(*Array); !isArray || !reflect.DeepEqual(array.Snapshot(), []Element{"a"}) {
+
+		t.Errorf("decoded %v, expected an array with [a]", decoded)
+	}
+}
+
+func TestArrayDecodingErrors(t *testing.T) {
+
+	var tests = []struct {
+		name     string
+		target   *Array
+		data     string
+		expected error
+	}{
+		{"unregistered type", new(Array), `{"type":"unknownType","elements":[]}`, ErrTypeNotRegistered},
+		{"mismatching type", ArrayOfType("int"), `{"type":"string","elements":["a"]}`, ErrTypeMismatch},
+	}
+
+	for _, test := range tests {
+
+		if err := json.Unmarshal([]byte(test.data), test.target); !errors.Is(err, test.expected) {
+
+			t.Errorf("%s: got %v, expected %v", test.name, err, test.expected)
+		}
+	}
+
+	if _, err := json.Marshal(new(Array)); !errors.Is(err, ErrTypeNotSet) {
+
+		t.Errorf("encoding an untyped array: got %v, expected %v", err, ErrTypeNotSet)
+	}
+}
@@ -0,0 +1,150 @@
+//
+// Copyright (c) 2016, Adrian Zubarev (a.k.a. DevAndArtist)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// * Redistributions of source code must retain the above copyright notice, this
+//   list of conditions and the following disclaimer.
+//
+// * Redistributions in binary form must reproduce the above copyright notice,
+//   this list of conditions and the following disclaimer in the documentation
+//   and/or other materials provided with the distribution.
+//
+// * Neither the name of Go-Array nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package array
+
+import "reflect"
+import "testing"
+
+func TestTypedArrayFunctionalHelpers(t *testing.T) {
+
+	var isEven = func(element int) bool { return element%2 == 0 }
+	var double = func(element int) int { return element * 2 }
+
+	var tests = []struct {
+		name     string
+		elements []int
+		filtered []int
+		mapped   []int
+		found    int
+		hasFound bool
+	}{
+		{"mixed", []int{1, 2, 3, 4}, []int{2, 4}, []int{2, 4, 6, 8}, 2, true},
+		{"only odd", []int{1, 3}, []int{}, []int{2, 6}, 0, false},
+		{"empty", []int{}, []int{}, []int{}, 0, false},
+	}
+
+	for _, test := range tests {
+
+		var array = TypedArrayOf(test.elements...)
+
+		if filtered := array.Filter(isEven).ToSlice(); !reflect.DeepEqual(filtered, test.filtered) {
+
+			t.Errorf("%s: filtered %v, expected %v", test.name, filtered, test.filtered)
+		}
+
+		if mapped := array.Map(double).ToSlice(); !reflect.DeepEqual(mapped, test.mapped) {
+
+			t.Errorf("%s: mapped %v, expected %v", test.name, mapped, test.mapped)
+		}
+
+		if found, hasFound := array.Find(isEven); found != test.found || hasFound != test.hasFound {
+
+			t.Errorf("%s: found (%v, %v), expected (%v, %v)", test.name, found, hasFound, test.found, test.hasFound)
+		}
+
+		if elements := array.ToSlice(); !reflect.DeepEqual(elements, test.elements) {
+
+			t.Errorf("%s: helpers modified the array to %v", test.name, elements)
+		}
+	}
+}
+
+func TestTypedArrayEachStopsEarly(t *testing.T) {
+
+	var array = TypedArrayOf(1, 2, 3, 4)
+	var visited = []int{}
+
+	array.Each(func(index int, element int) bool {
+
+		visited = append(visited, element)
+		array.Append(element) // the array is not locked while body runs
+		return index < 1
+	})
+
+	if !reflect.DeepEqual(visited, []int{1, 2}) {
+
+		t.Errorf("visited %v, expected [1 2]", visited)
+	}
+
+	if count := array.Count(); count != 6 {
+
+		t.Errorf("got %d elements, expected 6", count)
+	}
+}
+
+func TestTypedArraySortByAndMapTyped(t *testing.T) {
+
+	type pair struct {
+		key   int
+		value string
+	}
+
+	var array = TypedArrayOf(pair{2, "a"}, pair{1, "b"}, pair{2, "c"}, pair{1, "d"})
+	array.SortBy(func(lhs pair, rhs pair) bool { return lhs.key < rhs.key })
+
+	var values = MapTyped(array, func(element pair) string { return element.value }).ToSlice()
+	if !reflect.DeepEqual(values, []string{"b", "d", "a", "c"}) {
+
+		t.Errorf("sorted values %v, expected a stable sort [b d a c]", values)
+	}
+}
+
+func TestArrayFunctionalHelpers(t *testing.T) {
+
+	var array = ArrayOfType("int")
+	for _, element := range []int{3, 1, 2} {
+
+		array.Append(element)
+	}
+
+	var isOdd = func(element Element) bool { return element.(int)%2 == 1 }
+	array.SortBy(func(lhs Element, rhs Element) bool { return lhs.(int) < rhs.(int) })
+
+	var tests = []struct {
+		name     string
+		actual   interface{}
+		expected interface{}
+	}{
+		{"sorted", array.Snapshot(), []Element{1, 2, 3}},
+		{"filtered", array.Filter(isOdd).Snapshot(), []Element{1, 3}},
+		{"mapped", array.Map("string", func(element Element) Element { return string(rune('a' + element.(int))) }).Snapshot(), []Element{"b", "c", "d"}},
+		{"found", array.Find(isOdd), Element(1)},
+		{"not found", array.Find(func(element Element) bool { return false }), nil},
+	}
+
+	for _, test := range tests {
+
+		if !reflect.DeepEqual(test.actual, test.expected) {
+
+			t.Errorf("%s: got %v, expected %v", test.name, test.actual, test.expected)
+		}
+	}
+}
@@ -0,0 +1,401 @@
+//
+// Copyright (c) 2016, Adrian Zubarev (a.k.a. DevAndArtist)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// * Redistributions of source code must retain the above copyright notice, this
+//   list of conditions and the following disclaimer.
+//
+// * Redistributions in binary form must reproduce the above copyright notice,
+//   this list of conditions and the following disclaimer in the documentation
+//   and/or other materials provided with the distribution.
+//
+// * Neither the name of Go-Array nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package array
+
+import "fmt"
+import "sync"
+
+//==============--------------------------------------------==============//
+//==============------------------ types -------------------==============//
+//==============--------------------------------------------==============//
+
+// Set is an unordered, hashed collection of unique elements. Insert, Remove
+// and Contains run in O(1) instead of the linear scan used by Array.
+type Set[T comparable] struct {
+	elements map[T]struct{} // Element container
+	guard    sync.Mutex     // Cuncurrency guard
+}
+
+// OrderedSet behaves like Set but remembers the insertion order of its
+// elements, which keeps message fan-out deterministic.
+//
+// Clones are copy-on-write snapshots: Clone only shares the storage in O(1)
+// and whichever set is modified first copies it.
+type OrderedSet[T comparable] struct {
+	entries []orderedEntry[T] // Insertion ordered elements, including removed ones
+	indices map[T]int         // Element to entry index
+	removed int               // Number of removed entries in entries
+	shared  bool              // Storage is shared with a clone and must be copied before writing
+	guard   sync.Mutex        // Cuncurrency guard
+}
+
+type orderedEntry[T comparable] struct {
+	element T
+	removed bool
+}
+
+// ==============--------------------------------------------==============//
+// ==============------------- set constructors -------------==============//
+// ==============--------------------------------------------==============//
+func SetOf[T comparable](elements ...T) *Set[T] {
+
+	var set = new(Set[T])
+	set.elements = make(map[T]struct{}, len(elements))
+
+	for _, element := range elements {
+
+		set.elements[element] = struct{}{}
+	}
+	return set
+}
+
+func OrderedSetOf[T comparable](elements ...T) *OrderedSet[T] {
+
+	var set = new(OrderedSet[T])
+	set.indices = make(map[T]int, len(elements))
+
+	for _, element := range elements {
+
+		set.insert(element)
+	}
+	return set
+}
+
+//==============--------------------------------------------==============//
+//==============------------------- Set --------------------==============//
+//==============--------------------------------------------==============//
+
+// Insert adds the element and reports whether it was not yet part of the set.
+func (set *Set[T]) Insert(element T) bool {
+
+	set.guard.Lock()
+	var _, exists = set.elements[element]
+	set.elements[element] = struct{}{}
+	set.guard.Unlock()
+
+	return !exists
+}
+
+// Remove deletes the element and reports whether it was part of the set.
+func (set *Set[T]) Remove(element T) bool {
+
+	set.guard.Lock()
+	var _, exists = set.elements[element]
+	delete(set.elements, element)
+	set.guard.Unlock()
+
+	return exists
+}
+
+func (set *Set[T]) RemoveAll() {
+
+	set.guard.Lock()
+	set.elements = make(map[T]struct{})
+	set.guard.Unlock()
+}
+
+func (set *Set[T]) Contains(element T) bool {
+
+	set.guard.Lock()
+	var _, exists = set.elements[element]
+	set.guard.Unlock()
+
+	return exists
+}
+
+func (set *Set[T]) Count() int {
+
+	set.guard.Lock()
+	var count = len(set.elements)
+	set.guard.Unlock()
+
+	return count
+}
+
+func (set *Set[T]) IsEmpty() bool {
+
+	return set.Count() == 0
+}
+
+// Elements returns the elements in no particular order.
+func (set *Set[T]) Elements() []T {
+
+	set.guard.Lock()
+	var elements = make([]T, 0, len(set.elements))
+	for element := range set.elements {
+
+		elements = append(elements, element)
+	}
+	set.guard.Unlock()
+
+	return elements
+}
+
+func (set *Set[T]) Clone() *Set[T] {
+
+	return SetOf(set.Elements()...)
+}
+
+func (set *Set[T]) Union(other *Set[T]) *Set[T] {
+
+	var union = set.Clone()
+	for _, element := range other.Elements() {
+
+		union.elements[element] = struct{}{}
+	}
+	return union
+}
+
+func (set *Set[T]) Difference(other *Set[T]) *Set[T] {
+
+	var difference = set.Clone()
+	for _, element := range other.Elements() {
+
+		delete(difference.elements, element)
+	}
+	return difference
+}
+
+func (set *Set[T]) Intersection(other *Set[T]) *Set[T] {
+
+	var intersection = SetOf[T]()
+	for _, element := range other.Elements() {
+
+		if set.Contains(element) {
+
+			intersection.elements[element] = struct{}{}
+		}
+	}
+	return intersection
+}
+
+func (set *Set[T]) String() string {
+
+	return fmt.Sprintf("Set <%p> with elements: %v", set, set.Elements())
+}
+
+//==============--------------------------------------------==============//
+//==============--------------- OrderedSet -----------------==============//
+//==============--------------------------------------------==============//
+
+// Insert appends the element if it is not yet part of the set and reports
+// whether it was added.
+func (set *OrderedSet[T]) Insert(element T) bool {
+
+	set.guard.Lock()
+	var inserted = set.insert(element)
+	set.guard.Unlock()
+
+	return inserted
+}
+
+// Remove deletes the element and reports whether it was part of the set.
+func (set *OrderedSet[T]) Remove(element T) bool {
+
+	set.guard.Lock()
+	var index, exists = set.indices[element]
+	if exists {
+
+		set.unshare()
+		index = set.indices[element]
+
+		delete(set.indices, element)
+		set.entries[index].removed = true
+		set.removed++
+
+		// compact once most entries are removed, which keeps removal amortized O(1)
+		if set.removed > len(set.entries)/2 {
+
+			set.compact()
+		}
+	}
+	set.guard.Unlock()
+
+	return exists
+}
+
+func (set *OrderedSet[T]) RemoveAll() {
+
+	set.guard.Lock()
+	set.entries = nil
+	set.indices = make(map[T]int)
+	set.removed = 0
+	set.shared = false
+	set.guard.Unlock()
+}
+
+func (set *OrderedSet[T]) Contains(element T) bool {
+
+	set.guard.Lock()
+	var _, exists = set.indices[element]
+	set.guard.Unlock()
+
+	return exists
+}
+
+func (set *OrderedSet[T]) Count() int {
+
+	set.guard.Lock()
+	var count = len(set.indices)
+	set.guard.Unlock()
+
+	return count
+}
+
+func (set *OrderedSet[T]) IsEmpty() bool {
+
+	return set.Count() == 0
+}
+
+// Elements returns the elements in insertion order.
+func (set *OrderedSet[T]) Elements() []T {
+
+	set.guard.Lock()
+	var elements = set.elementSlice()
+	set.guard.Unlock()
+
+	return elements
+}
+
+// Clone returns a copy-on-write snapshot of the set in O(1).
+func (set *OrderedSet[T]) Clone() *OrderedSet[T] {
+
+	set.guard.Lock()
+	set.shared = true
+
+	var clone = new(OrderedSet[T])
+	clone.entries = set.entries
+	clone.indices = set.indices
+	clone.removed = set.removed
+	clone.shared = true
+	set.guard.Unlock()
+
+	return clone
+}
+
+// Union keeps the order of the receiver and appends the new elements of other.
+func (set *OrderedSet[T]) Union(other *OrderedSet[T]) *OrderedSet[T] {
+
+	var union = set.Clone()
+	for _, element := range other.Elements() {
+
+		union.insert(element)
+	}
+	return union
+}
+
+func (set *OrderedSet[T]) Difference(other *OrderedSet[T]) *OrderedSet[T] {
+
+	var excluded = SetOf(other.Elements()...)
+	var difference = OrderedSetOf[T]()
+	for _, element := range set.Elements() {
+
+		if !excluded.Contains(element) {
+
+			difference.insert(element)
+		}
+	}
+	return difference
+}
+
+func (set *OrderedSet[T]) Intersection(other *OrderedSet[T]) *OrderedSet[T] {
+
+	var included = SetOf(other.Elements()...)
+	var intersection = OrderedSetOf[T]()
+	for _, element := range set.Elements() {
+
+		if included.Contains(element) {
+
+			intersection.insert(element)
+		}
+	}
+	return intersection
+}
+
+func (set *OrderedSet[T]) String() string {
+
+	return fmt.Sprintf("OrderedSet <%p> with elements: %v", set, set.Elements())
+}
+
+//==============--------------------------------------------==============//
+//==============-------------- private helper --------------==============//
+//==============--------------------------------------------==============//
+
+// The following helpers expect the guard to be locked by the caller (or the
+// set not to be shared yet).
+func (set *OrderedSet[T]) insert(element T) bool {
+
+	if _, exists := set.indices[element]; exists {
+
+		return false
+	}
+	set.unshare()
+	set.indices[element] = len(set.entries)
+	set.entries = append(set.entries, orderedEntry[T]{element: element})
+	return true
+}
+
+func (set *OrderedSet[T]) elementSlice() []T {
+
+	var elements = make([]T, 0, len(set.indices))
+	for _, entry := range set.entries {
+
+		if !entry.removed {
+
+			elements = append(elements, entry.element)
+		}
+	}
+	return elements
+}
+
+func (set *OrderedSet[T]) compact() {
+
+	var elements = set.elementSlice()
+
+	set.entries = make([]orderedEntry[T], 0, len(elements))
+	set.indices = make(map[T]int, len(elements))
+	set.removed = 0
+	set.shared = false
+
+	for _, element := range elements {
+
+		set.insert(element)
+	}
+}
+
+// unshare copies the storage if it is shared with a clone.
+func (set *OrderedSet[T]) unshare() {
+
+	if set.shared {
+
+		set.compact()
+	}
+}
@@ -0,0 +1,161 @@
+//
+// Copyright (c) 2016, Adrian Zubarev (a.k.a. DevAndArtist)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// * Redistributions of source code must retain the above copyright notice, this
+//   list of conditions and the following disclaimer.
+//
+// * Redistributions in binary form must reproduce the above copyright notice,
+//   this list of conditions and the following disclaimer in the documentation
+//   and/or other materials provided with the distribution.
+//
+// * Neither the name of Go-Array nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package array
+
+import "reflect"
+import "sort"
+import "testing"
+
+func sortedElements(set *Set[int]) []int {
+
+	var elements = set.Elements()
+	sort.Ints(elements)
+	return elements
+}
+
+func TestSetAlgebra(t *testing.T) {
+
+	var tests = []struct {
+		name         string
+		lhs          []int
+		rhs          []int
+		union        []int
+		difference   []int
+		intersection []int
+	}{
+		{"disjoint", []int{1, 2}, []int{3, 4}, []int{1, 2, 3, 4}, []int{1, 2}, []int{}},
+		{"overlapping", []int{1, 2, 3}, []int{2, 3, 4}, []int{1, 2, 3, 4}, []int{1}, []int{2, 3}},
+		{"subset", []int{1, 2}, []int{1, 2, 3}, []int{1, 2, 3}, []int{}, []int{1, 2}},
+		{"equal", []int{1, 2}, []int{2, 1}, []int{1, 2}, []int{}, []int{1, 2}},
+		{"empty rhs", []int{1, 2}, []int{}, []int{1, 2}, []int{1, 2}, []int{}},
+		{"empty lhs", []int{}, []int{1}, []int{1}, []int{}, []int{}},
+	}
+
+	for _, test := range tests {
+
+		var lhs = SetOf(test.lhs...)
+		var rhs = SetOf(test.rhs...)
+
+		if union := sortedElements(lhs.Union(rhs)); !reflect.DeepEqual(union, test.union) {
+
+			t.Errorf("%s: union is %v, expected %v", test.name, union, test.union)
+		}
+
+		if difference := sortedElements(lhs.Difference(rhs)); !reflect.DeepEqual(difference, test.difference) {
+
+			t.Errorf("%s: difference is %v, expected %v", test.name, difference, test.difference)
+		}
+
+		if intersection := sortedElements(lhs.Intersection(rhs)); !reflect.DeepEqual(intersection, test.intersection) {
+
+			t.Errorf("%s: intersection is %v, expected %v", test.name, intersection, test.intersection)
+		}
+
+		if elements := sortedElements(lhs); !reflect.DeepEqual(elements, sortedElements(SetOf(test.lhs...))) {
+
+			t.Errorf("%s: set algebra modified the receiver to %v", test.name, elements)
+		}
+	}
+}
+
+func TestOrderedSetAlgebra(t *testing.T) {
+
+	var tests = []struct {
+		name         string
+		lhs          []int
+		rhs          []int
+		union        []int
+		difference   []int
+		intersection []int
+	}{
+		{"disjoint", []int{2, 1}, []int{4, 3}, []int{2, 1, 4, 3}, []int{2, 1}, []int{}},
+		{"overlapping", []int{3, 1, 2}, []int{4, 2, 3}, []int{3, 1, 2, 4}, []int{1}, []int{3, 2}},
+		{"subset", []int{2, 1}, []int{1, 2, 3}, []int{2, 1, 3}, []int{}, []int{2, 1}},
+		{"empty rhs", []int{1, 2}, []int{}, []int{1, 2}, []int{1, 2}, []int{}},
+		{"empty lhs", []int{}, []int{1}, []int{1}, []int{}, []int{}},
+	}
+
+	for _, test := range tests {
+
+		var lhs = OrderedSetOf(test.lhs...)
+		var rhs = OrderedSetOf(test.rhs...)
+
+		if union := lhs.Union(rhs).Elements(); !reflect.DeepEqual(union, test.union) {
+
+			t.Errorf("%s: union is %v, expected %v", test.name, union, test.union)
+		}
+
+		if difference := lhs.Difference(rhs).Elements(); !reflect.DeepEqual(difference, test.difference) {
+
+			t.Errorf("%s: difference is %v, expected %v", test.name, difference, test.difference)
+		}
+
+		if intersection := lhs.Intersection(rhs).Elements(); !reflect.DeepEqual(intersection, test.intersection) {
+
+			t.Errorf("%s: intersection is %v, expected %v", test.name, intersection, test.intersection)
+		}
+
+		if elements := lhs.Elements(); !reflect.DeepEqual(elements, test.lhs) {
+
+			t.Errorf("%s: set algebra modified the receiver to %v", test.name, elements)
+		}
+	}
+}
+
+func TestOrderedSetInsertAndRemove(t *testing.T) {
+
+	var set = OrderedSetOf(1, 2, 3)
+
+	var tests = []struct {
+		name     string
+		result   bool
+		expected bool
+	}{
+		{"insert new", set.Insert(4), true},
+		{"insert existing", set.Insert(2), false},
+		{"remove existing", set.Remove(1), true},
+		{"remove missing", set.Remove(9), false},
+		{"remove again", set.Remove(1), false},
+	}
+
+	for _, test := range tests {
+
+		if test.result != test.expected {
+
+			t.Errorf("%s: got %v, expected %v", test.name, test.result, test.expected)
+		}
+	}
+
+	if elements := set.Elements(); !reflect.DeepEqual(elements, []int{2, 3, 4}) {
+
+		t.Errorf("got elements %v, expected [2 3 4]", elements)
+	}
+}
@@ -0,0 +1,96 @@
+//
+// Copyright (c) 2016, Adrian Zubarev (a.k.a. DevAndArtist)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// * Redistributions of source code must retain the above copyright notice, this
+//   list of conditions and the following disclaimer.
+//
+// * Redistributions in binary form must reproduce the above copyright notice,
+//   this list of conditions and the following disclaimer in the documentation
+//   and/or other materials provided with the distribution.
+//
+// * Neither the name of Go-Array nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package array
+
+import "errors"
+import "testing"
+
+func TestTrySentinelErrors(t *testing.T) {
+
+	var typed = func() *Array {
+
+		var array = ArrayOfType("string")
+		array.Append("a")
+		return array
+	}
+
+	var tests = []struct {
+		name     string
+		call     func() error
+		expected error
+	}{
+		{"unknown type", func() error { _, err := TryArrayOfType("unknownType"); return err }, ErrTypeNotRegistered},
+		{"set type twice", func() error { return typed().TrySetType("int") }, ErrTypeAlreadySet},
+		{"set unknown type", func() error { return new(Array).TrySetType("unknownType") }, ErrTypeNotRegistered},
+		{"type not set", func() error { _, err := new(Array).TryType(); return err }, ErrTypeNotSet},
+		{"append without type", func() error { return new(Array).TryAppend("a") }, ErrTypeNotSet},
+		{"append mismatch", func() error { return typed().TryAppend(1) }, ErrTypeMismatch},
+		{"insert mismatch", func() error { return typed().TryInsertAtIndex(1, 0) }, ErrTypeMismatch},
+		{"remove mismatch", func() error { return typed().TryRemove(1) }, ErrTypeMismatch},
+		{"contains mismatch", func() error { _, err := typed().TryContains(1); return err }, ErrTypeMismatch},
+		{"index of mismatch", func() error { _, err := typed().TryIndexOf(1); return err }, ErrTypeMismatch},
+		{"set at index mismatch", func() error { return typed().TrySetAtIndex(1, 0) }, ErrTypeMismatch},
+		{"set at invalid index", func() error { return typed().TrySetAtIndex("b", 1) }, ErrIndexOutOfRange},
+		{"set at negative index", func() error { return typed().TrySetAtIndex("b", -1) }, ErrIndexOutOfRange},
+		{"typed set at invalid index", func() error { return TypedArrayOf(1).TrySetAtIndex(2, 1) }, ErrIndexOutOfRange},
+		{"valid append", func() error { return typed().TryAppend("b") }, nil},
+		{"valid set at index", func() error { return typed().TrySetAtIndex("b", 0) }, nil},
+	}
+
+	for _, test := range tests {
+
+		var err = test.call()
+		if test.expected == nil && err != nil {
+
+			t.Errorf("%s: unexpected error %v", test.name, err)
+
+		} else if !errors.Is(err, test.expected) {
+
+			t.Errorf("%s: got %v, expected %v", test.name, err, test.expected)
+		}
+	}
+}
+
+func TestTryOperationsLeaveArrayUnchanged(t *testing.T) {
+
+	var array = ArrayOfType("int")
+	array.Append(1)
+
+	if err := array.TryAppend("a"); err == nil {
+
+		t.Fatalf("expected an error for a mismatching element")
+	}
+
+	if count := array.Count(); count != 1 {
+
+		t.Errorf("got %d elements after a failed append, expected 1", count)
+	}
+}
@@ -0,0 +1,365 @@
+//
+// Copyright (c) 2016, Adrian Zubarev (a.k.a. DevAndArtist)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// * Redistributions of source code must retain the above copyright notice, this
+//   list of conditions and the following disclaimer.
+//
+// * Redistributions in binary form must reproduce the above copyright notice,
+//   this list of conditions and the following disclaimer in the documentation
+//   and/or other materials provided with the distribution.
+//
+// * Neither the name of Go-Array nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package array
+
+import "fmt"
+import "sort"
+import "sync"
+
+//==============--------------------------------------------==============//
+//==============------------------ types -------------------==============//
+//==============--------------------------------------------==============//
+
+// TypedArray is the compile-time checked counterpart of Array. Elements are
+// compared with == instead of reflect.DeepEqual, so no type registration is
+// needed and a mismatching element does not compile.
+type TypedArray[T comparable] struct {
+	elements []T        // Element container
+	guard    sync.Mutex // Cuncurrency guard
+}
+
+// ==============--------------------------------------------==============//
+// ==============------------- array constructor ------------==============//
+// ==============--------------------------------------------==============//
+func TypedArrayOf[T comparable](elements ...T) *TypedArray[T] {
+
+	var array = new(TypedArray[T])
+	array.elements = append([]T{}, elements...)
+	return array
+}
+
+// ==============--------------------------------------------==============//
+// ==============-------------- elements adder --------------==============//
+// ==============--------------------------------------------==============//
+func (array *TypedArray[T]) Append(newElement T) {
+
+	array.guard.Lock()
+	array.elements = append(array.elements, newElement)
+	array.guard.Unlock()
+}
+
+func (array *TypedArray[T]) AppendUnique(newElement T) {
+
+	array.guard.Lock()
+	if array.indexOf(newElement) < 0 {
+
+		array.elements = append(array.elements, newElement)
+	}
+	array.guard.Unlock()
+}
+
+func (array *TypedArray[T]) InsertAtIndex(newElement T, index int) {
+
+	array.guard.Lock()
+	var anIndex = 0
+
+	if index > anIndex {
+
+		anIndex = index
+	}
+
+	if anIndex > len(array.elements) {
+
+		array.elements = append(array.elements, newElement)
+
+	} else {
+
+		var lhs = array.elements[:anIndex]
+		var rhs = append([]T{newElement}, array.elements[anIndex:]...)
+		array.elements = append(lhs, rhs...)
+	}
+	array.guard.Unlock()
+}
+
+// ==============--------------------------------------------==============//
+// ==============-------------- element remover -------------==============//
+// ==============--------------------------------------------==============//
+func (array *TypedArray[T]) RemoveAtIndex(index int) T {
+
+	var element T
+
+	array.guard.Lock()
+	if index >= 0 && index < len(array.elements) {
+
+		element = array.elements[index]
+		array.elements = append(array.elements[:index], array.elements[index+1:]...)
+	}
+	array.guard.Unlock()
+
+	return element
+}
+
+func (array *TypedArray[T]) Remove(element T) {
+
+	array.guard.Lock()
+	var index = array.indexOf(element)
+
+	if index >= 0 {
+
+		array.elements = append(array.elements[:index], array.elements[index+1:]...)
+	}
+	array.guard.Unlock()
+}
+
+func (array *TypedArray[T]) RemoveFirst() T {
+
+	return array.RemoveAtIndex(0)
+}
+
+func (array *TypedArray[T]) RemoveLast() T {
+
+	return array.RemoveAtIndex(array.Count() - 1)
+}
+
+func (array *TypedArray[T]) RemoveAll() {
+
+	array.guard.Lock()
+	array.elements = []T{}
+	array.guard.Unlock()
+}
+
+// ==============--------------------------------------------==============//
+// ==============-------------- element counter -------------==============//
+// ==============--------------------------------------------==============//
+func (array *TypedArray[T]) Count() int {
+
+	array.guard.Lock()
+	var count = len(array.elements)
+	array.guard.Unlock()
+
+	return count
+}
+
+func (array *TypedArray[T]) IsEmpty() bool {
+
+	return array.Count() == 0
+}
+
+// ==============--------------------------------------------==============//
+// ==============---------- element/index searcher ----------==============//
+// ==============--------------------------------------------==============//
+func (array *TypedArray[T]) Contains(element T) bool {
+
+	return array.IndexOf(element) >= 0
+}
+
+func (array *TypedArray[T]) IndexOf(element T) int {
+
+	array.guard.Lock()
+	var index = array.indexOf(element)
+	array.guard.Unlock()
+
+	return index
+}
+
+//==============--------------------------------------------==============//
+//==============-------------- element getter --------------==============//
+//==============--------------------------------------------==============//
+
+// ElementAtIndex returns the zero value of T for an index out of range, just
+// like Array returns nil.
+func (array *TypedArray[T]) ElementAtIndex(index int) T {
+
+	var element T
+
+	array.guard.Lock()
+	if index >= 0 && index < len(array.elements) {
+
+		element = array.elements[index]
+	}
+	array.guard.Unlock()
+
+	return element
+}
+
+func (array *TypedArray[T]) First() T {
+
+	return array.ElementAtIndex(0)
+}
+
+func (array *TypedArray[T]) Last() T {
+
+	return array.ElementAtIndex(array.Count() - 1)
+}
+
+// ==============--------------------------------------------==============//
+// ==============-------------- element setter --------------==============//
+// ==============--------------------------------------------==============//
+// SetAtIndex panics like a slice does if the index is out of range.
+func (array *TypedArray[T]) SetAtIndex(element T, index int) {
+
+	if setError := array.TrySetAtIndex(element, index); setError != nil {
+
+		panic(setError)
+	}
+}
+
+func (array *TypedArray[T]) TrySetAtIndex(element T, index int) error {
+
+	array.guard.Lock()
+	defer array.guard.Unlock()
+
+	if index < 0 || index >= len(array.elements) {
+
+		return fmt.Errorf("%w: index %d for array <%p> with elements: %v", ErrIndexOutOfRange, index, array, array.elements)
+	}
+	array.elements[index] = element
+	return nil
+}
+
+// ==============--------------------------------------------==============//
+// ==============-------------- array copying ---------------==============//
+// ==============--------------------------------------------==============//
+func (array *TypedArray[T]) Clone() *TypedArray[T] {
+
+	array.guard.Lock()
+	var newArray = TypedArrayOf(array.elements...)
+	array.guard.Unlock()
+
+	return newArray
+}
+
+//==============--------------------------------------------==============//
+//==============----------- iteration/functional -----------==============//
+//==============--------------------------------------------==============//
+
+// Snapshot copies the elements under a single lock acquisition, so the
+// result is a consistent view even if other goroutines modify the array.
+func (array *TypedArray[T]) Snapshot() []T {
+
+	array.guard.Lock()
+	var elements = append([]T{}, array.elements...)
+	array.guard.Unlock()
+
+	return elements
+}
+
+// ToSlice is Snapshot under the name the untyped Array uses.
+func (array *TypedArray[T]) ToSlice() []T {
+
+	return array.Snapshot()
+}
+
+// Each calls body for every element of a snapshot until body returns false.
+// The array is not locked while body runs, so body may modify it.
+func (array *TypedArray[T]) Each(body func(index int, element T) bool) {
+
+	for index, element := range array.Snapshot() {
+
+		if !body(index, element) {
+			break
+		}
+	}
+}
+
+func (array *TypedArray[T]) Filter(isIncluded func(element T) bool) *TypedArray[T] {
+
+	var filtered = TypedArrayOf[T]()
+	for _, element := range array.Snapshot() {
+
+		if isIncluded(element) {
+
+			filtered.elements = append(filtered.elements, element)
+		}
+	}
+	return filtered
+}
+
+// Map keeps the element type, use MapTyped to produce a different one.
+func (array *TypedArray[T]) Map(transform func(element T) T) *TypedArray[T] {
+
+	return MapTyped(array, transform)
+}
+
+// Find returns the first element matching predicate.
+func (array *TypedArray[T]) Find(predicate func(element T) bool) (T, bool) {
+
+	for _, element := range array.Snapshot() {
+
+		if predicate(element) {
+
+			return element, true
+		}
+	}
+	var none T
+	return none, false
+}
+
+// SortBy sorts the elements in place, keeping the order of equal elements.
+func (array *TypedArray[T]) SortBy(isOrderedBefore func(lhs T, rhs T) bool) {
+
+	array.guard.Lock()
+	sort.SliceStable(array.elements, func(i int, j int) bool {
+
+		return isOrderedBefore(array.elements[i], array.elements[j])
+	})
+	array.guard.Unlock()
+}
+
+// MapTyped is a function, because methods can not introduce the type parameter U.
+func MapTyped[T comparable, U comparable](array *TypedArray[T], transform func(element T) U) *TypedArray[U] {
+
+	var mapped = TypedArrayOf[U]()
+	for _, element := range array.Snapshot() {
+
+		mapped.elements = append(mapped.elements, transform(element))
+	}
+	return mapped
+}
+
+// ==============--------------------------------------------==============//
+// ==============-------------- array printer ---------------==============//
+// ==============--------------------------------------------==============//
+func (array *TypedArray[T]) String() string {
+
+	array.guard.Lock()
+	var description = fmt.Sprintf("TypedArray <%p> of type <%T> with elements: %v", array, *new(T), array.elements)
+	array.guard.Unlock()
+
+	return description
+}
+
+//==============--------------------------------------------==============//
+//==============-------------- private helper --------------==============//
+//==============--------------------------------------------==============//
+
+// indexOf expects the guard to be locked by the caller.
+func (array *TypedArray[T]) indexOf(element T) int {
+
+	for index, anElement := range array.elements {
+
+		if anElement == element {
+
+			return index
+		}
+	}
+	return -1
+}
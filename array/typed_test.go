@@ -0,0 +1,129 @@
+//
+// Copyright (c) 2016, Adrian Zubarev (a.k.a. DevAndArtist)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// * Redistributions of source code must retain the above copyright notice, this
+//   list of conditions and the following disclaimer.
+//
+// * Redistributions in binary form must reproduce the above copyright notice,
+//   this list of conditions and the following disclaimer in the documentation
+//   and/or other materials provided with the distribution.
+//
+// * Neither the name of Go-Array nor the names of its
+//   contributors may be used to endorse or promote products derived from
+//   this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+//
+
+package array
+
+import "errors"
+import "reflect"
+import "testing"
+
+func TestTypedArrayOperations(t *testing.T) {
+
+	var tests = []struct {
+		name      string
+		operation func(array *TypedArray[int])
+		expected  []int
+	}{
+		{"append", func(array *TypedArray[int]) { array.Append(4) }, []int{1, 2, 3, 4}},
+		{"append unique existing", func(array *TypedArray[int]) { array.AppendUnique(2) }, []int{1, 2, 3}},
+		{"append unique new", func(array *TypedArray[int]) { array.AppendUnique(5) }, []int{1, 2, 3, 5}},
+		{"insert at front", func(array *TypedArray[int]) { array.InsertAtIndex(0, 0) }, []int{0, 1, 2, 3}},
+		{"insert in the middle", func(array *TypedArray[int]) { array.InsertAtIndex(9, 2) }, []int{1, 2, 9, 3}},
+		{"insert at negative index", func(array *TypedArray[int]) { array.InsertAtIndex(9, -4) }, []int{9, 1, 2, 3}},
+		{"insert past the end", func(array *TypedArray[int]) { array.InsertAtIndex(9, 10) }, []int{1, 2, 3, 9}},
+		{"remove at index", func(array *TypedArray[int]) { array.RemoveAtIndex(1) }, []int{1, 3}},
+		{"remove at invalid index", func(array *TypedArray[int]) { array.RemoveAtIndex(3) }, []int{1, 2, 3}},
+		{"remove element", func(array *TypedArray[int]) { array.Remove(3) }, []int{1, 2}},
+		{"remove missing element", func(array *TypedArray[int]) { array.Remove(7) }, []int{1, 2, 3}},
+		{"remove first", func(array *TypedArray[int]) { array.RemoveFirst() }, []int{2, 3}},
+		{"remove last", func(array *TypedArray[int]) { array.RemoveLast() }, []int{1, 2}},
+		{"remove all", func(array *TypedArray[int]) { array.RemoveAll() }, []int{}},
+		{"set at index", func(array *TypedArray[int]) { array.SetAtIndex(8, 0) }, []int{8, 2, 3}},
+	}
+
+	for _, test := range tests {
+
+		var array = TypedArrayOf(1, 2, 3)
+		test.operation(array)
+
+		if elements := array.ToSlice(); !reflect.DeepEqual(elements, test.expected) {
+
+			t.Errorf("%s: got %v, expected %v", test.name, elements, test.expected)
+		}
+	}
+}
+
+func TestTypedArrayQueries(t *testing.T) {
+
+	var array = TypedArrayOf("a", "b", "c")
+	var empty = TypedArrayOf[string]()
+
+	var tests = []struct {
+		name     string
+		actual   interface{}
+		expected interface{}
+	}{
+		{"count", array.Count(), 3},
+		{"count of empty", empty.Count(), 0},
+		{"is empty", array.IsEmpty(), false},
+		{"is empty of empty", empty.IsEmpty(), true},
+		{"contains", array.Contains("b"), true},
+		{"contains missing", array.Contains("z"), false},
+		{"index of", array.IndexOf("c"), 2},
+		{"index of missing", array.IndexOf("z"), -1},
+		{"element at index", array.ElementAtIndex(1), "b"},
+		{"element at invalid index", array.ElementAtIndex(3), ""},
+		{"first", array.First(), "a"},
+		{"last", array.Last(), "c"},
+		{"first of empty", empty.First(), ""},
+		{"last of empty", empty.Last(), ""},
+	}
+
+	for _, test := range tests {
+
+		if !reflect.DeepEqual(test.actual, test.expected) {
+
+			t.Errorf("%s: got %v, expected %v", test.name, test.actual, test.expected)
+		}
+	}
+}
+
+func TestTypedArraySetAtIndexPanics(t *testing.T) {
+
+	for _, index := range []int{-1, 3} {
+
+		var array = TypedArrayOf(1, 2, 3)
+		func() {
+
+			defer func() {
+
+				if recovered := recover(); recovered == nil {
+
+					t.Errorf("index %d: expected a panic", index)
+
+				} else if err, isError := recovered.(error); !isError || !errors.Is(err, ErrIndexOutOfRange) {
+
+					t.Errorf("index %d: panicked with %v, expected %v", index, recovered, ErrIndexOutOfRange)
+				}
+			}()
+			array.SetAtIndex(9, index)
+		}()
+	}
+}
@@ -0,0 +1,162 @@
+//
+//  async.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+package bfs
+
+import . "fmt"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/bfs/command"
+
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/algorithm"
+
+import "sync"
+import "encoding/gob"
+
+// AsyncNode computes the BFS tree without synchronizing the levels through
+// the root (Bellman-Ford style): a node adopts every smaller level it hears
+// of and re-broadcasts it. Termination is detected with Dijkstra-Scholten:
+// every label is acknowledged, and a node acknowledges the label that engaged
+// it only after all of its own labels were acknowledged. The root therefore
+// knows the traversal is over once it holds no unacknowledged labels.
+type AsyncNode struct {
+	guard       sync.Mutex
+	host        Host
+	id          string
+	parentID    string
+	level       int64
+	root        bool
+	neighbors   []string
+	lastLevels  map[string]int64 // smallest level received from each neighbor
+	children    map[string]bool  // whether the neighbor's latest label named us as parent
+	engagedWith string           // sender of the label we have not acknowledged yet
+	deficit     int              // number of own labels not acknowledged yet
+	completed   bool
+	messages    int
+}
+
+// AsyncLabel is the value of AsyncLabelCommand.
+type AsyncLabel struct {
+	Level    int64
+	ParentID string
+}
+
+func init() {
+
+	gob.Register(AsyncLabel{})
+	algorithm.Register("async-bfs", func() algorithm.Algorithm { return new(AsyncNode) })
+}
+
+func (node *AsyncNode) Init(environment algorithm.Environment) {
+
+	node.guard.Lock()
+	node.host = environment.Host
+	node.id = environment.ID
+	node.parentID = ""
+	node.level = -1
+	node.neighbors = append([]string{}, environment.Neighbors...)
+	node.lastLevels = make(map[string]int64)
+	node.children = make(map[string]bool)
+	node.guard.Unlock()
+}
+
+func (node *AsyncNode) HandleMessage(sender string, receiver string, command uint8, value interface{}) {
+
+	node.guard.Lock()
+	switch command {
+
+	case InitCommand:
+		node.root = true
+		node.parentID = node.id
+		node.level = 0
+		node.broadcast()
+
+	case AsyncLabelCommand:
+		var label = value.(AsyncLabel)
+
+		// labels of one neighbor only get smaller, an older one may arrive late
+		if lastLevel, known := node.lastLevels[sender]; !known || label.Level < lastLevel {
+
+			node.lastLevels[sender] = label.Level
+			node.children[sender] = label.ParentID == node.id
+		}
+
+		if node.level < 0 || label.Level+1 < node.level {
+
+			node.level = label.Level + 1
+			node.parentID = sender
+			node.broadcast()
+		}
+
+		if !node.root && len(node.engagedWith) == 0 {
+
+			node.engagedWith = sender
+
+		} else {
+
+			node.send(sender, AsyncAckCommand, nil)
+		}
+
+	case AsyncAckCommand:
+		node.deficit--
+
+	default:
+		Printf("[Async BFS Algorithm]: Unknown command \"%d\"- do nothing\n", command)
+	}
+	node.checkTermination()
+	node.guard.Unlock()
+}
+
+func (node *AsyncNode) Result() interface{} {
+
+	node.guard.Lock()
+	var children []string
+	for _, neighborID := range node.neighbors {
+
+		if node.children[neighborID] {
+
+			children = append(children, neighborID)
+		}
+	}
+	var result = Result{ID: node.id, ParentID: node.parentID, Level: node.level, Children: children, Messages: node.messages}
+	node.guard.Unlock()
+	return result
+}
+
+// The following helpers expect the guard to be locked by the caller.
+func (node *AsyncNode) broadcast() {
+
+	// the parent gets the label as well, this is how it learns about its children
+	for _, neighborID := range node.neighbors {
+
+		node.deficit++
+		node.send(neighborID, AsyncLabelCommand, AsyncLabel{Level: node.level, ParentID: node.parentID})
+	}
+}
+
+func (node *AsyncNode) checkTermination() {
+
+	if node.deficit > 0 {
+		return
+	}
+
+	if len(node.engagedWith) > 0 {
+
+		node.send(node.engagedWith, AsyncAckCommand, nil)
+		node.engagedWith = ""
+
+	} else if node.root && !node.completed {
+
+		node.completed = true
+		node.send("server", CompleteCommand, nil)
+	}
+}
+
+func (node *AsyncNode) send(receiver string, command uint8, value interface{}) {
+
+	node.messages++
+	node.host.SendMessage(node.id, receiver, command, value)
+}
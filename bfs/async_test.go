@@ -0,0 +1,123 @@
+//
+//  async_test.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+package bfs
+
+import . "fmt"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/bfs/command"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/graph"
+
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/algorithm"
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/algorithm/algorithmtest"
+
+import "testing"
+
+var idOf = algorithmtest.IDOf
+var graphOf = algorithmtest.UnweightedGraphOf
+
+// runOn starts the algorithm at the root of the graph and delivers every message.
+func runOn(graph *Graph, factory algorithm.Factory, root Vertex, seed int64) (*algorithmtest.Network, map[Vertex]algorithm.Algorithm) {
+
+	var network = algorithmtest.NetworkWith(seed)
+	var nodes = algorithmtest.NodesOf(graph, network, factory)
+
+	network.SendMessage("server", idOf(root), InitCommand, nil)
+	network.Deliver()
+	return network, nodes
+}
+
+var testGraphs = []struct {
+	name  string
+	graph *Graph
+}{
+	{"single edge", graphOf([][2]int{{0, 1}})},
+	{"path", graphOf([][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 4}})},
+	{"cycle", graphOf([][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 5}, {5, 0}})},
+	{"complete", graphOf([][2]int{{0, 1}, {0, 2}, {0, 3}, {1, 2}, {1, 3}, {2, 3}})},
+	{"random", algorithmtest.RandomGraph(12, 10, 1, 1)},
+	{"random dense", algorithmtest.RandomGraph(10, 25, 1, 2)},
+}
+
+// checkTree compares the levels with the hop distances from the root and
+// checks that parents and children agree.
+func checkTree(t *testing.T, name string, graph *Graph, root Vertex, nodes map[Vertex]algorithm.Algorithm) {
+
+	var distances = graph.HopDistances(root)
+	var results = make(map[string]Result)
+	for vertex, node := range nodes {
+
+		results[idOf(vertex)] = node.Result().(Result)
+	}
+
+	for _, vertex := range graph.Vertices() {
+
+		var result = results[idOf(vertex)]
+		if result.Level != int64(distances[vertex]) {
+
+			t.Errorf("%s: vertex %d has level %d, expected %d", name, vertex, result.Level, distances[vertex])
+		}
+
+		if vertex == root {
+
+			if result.ParentID != idOf(root) {
+
+				t.Errorf("%s: root has parent %s", name, result.ParentID)
+			}
+			continue
+		}
+
+		var parent = results[result.ParentID]
+		if parent.Level != result.Level-1 {
+
+			t.Errorf("%s: vertex %d has level %d, but its parent %s level %d", name, vertex, result.Level, result.ParentID, parent.Level)
+		}
+
+		var listed = false
+		for _, childID := range parent.Children {
+
+			listed = listed || childID == idOf(vertex)
+		}
+		if !listed {
+
+			t.Errorf("%s: parent %s does not list vertex %d among its children %v", name, result.ParentID, vertex, parent.Children)
+		}
+	}
+}
+
+func TestAsyncNodeDetectsTermination(t *testing.T) {
+
+	for _, test := range testGraphs {
+
+		for seed := int64(0); seed < 10; seed++ {
+
+			var name = Sprintf("%s, seed %d", test.name, seed)
+			var network, nodes = runOn(test.graph, func() algorithm.Algorithm { return new(AsyncNode) }, 0, seed)
+
+			// Dijkstra-Scholten: once the root knows, every label was acknowledged
+			var server = network.ServerMessages()
+			if len(server) != 1 || server[0].Sender != idOf(0) || server[0].Command != CompleteCommand {
+
+				t.Errorf("%s: the server received %v, expected a single completion of the root", name, server)
+
+			} else if server[0].InFlight != 0 {
+
+				t.Errorf("%s: the root completed with %d messages in flight", name, server[0].InFlight)
+			}
+
+			for vertex, node := range nodes {
+
+				var asyncNode = node.(*AsyncNode)
+				if asyncNode.deficit != 0 || asyncNode.engagedWith != "" {
+
+					t.Errorf("%s: vertex %d ends with deficit %d engaged with %q", name, vertex, asyncNode.deficit, asyncNode.engagedWith)
+				}
+			}
+			checkTree(t, name, test.graph, 0, nodes)
+		}
+	}
+}
@@ -6,19 +6,50 @@
 //  All rights reserved.
 //
 
+// Package bfs implements the layered distributed breadth-first search. A Node
+// can be embedded into any process that implements Host to deliver messages.
 package bfs
 
 import . "fmt"
-import . "./command"
-import . "./../array"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/bfs/command"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/array"
+
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/algorithm"
 
 import "sync"
 import "strings"
+import "encoding/gob"
+
+// Host is kept as an alias, every algorithm talks to the same kind of host.
+type Host = algorithm.Host
+
+// Result is what a node reports to the server once the traversal is done.
+type Result struct {
+	ID       string
+	ParentID string
+	Level    int64
+	Children []string
+	Messages int
+}
 
-type Host interface {
-	SendMessage(sender string, receiver string, command uint8, value interface{})
+// Snapshot is the state of a node at one point in time.
+type Snapshot struct {
+	ID         string
+	Labeled    bool
+	ParentID   string
+	Level      int64
+	Phase      int64
+	SendTo     []string
+	Children   []string
+	EchoedFrom map[string]bool // whether each node in SendTo echoed in the current phase
+	Completed  bool
+	Messages   int
 }
 
+// Node is idempotent: every message carries an Envelope, duplicates are
+// recognized by their sequence number and echoes that do not belong to the
+// phase the node waits for are dropped, so the algorithm stays correct over
+// transports that deliver at least once.
 type Node struct {
 	once       sync.Once
 	guard      sync.Mutex
@@ -27,10 +58,23 @@ type Node struct {
 	parentID   string
 	treeLevel  int64
 	labeled    bool
-	neighbors  *Array
-	sendTo     *Array
-	children   *Array
+	neighbors  *OrderedSet[string]
+	sendTo     *OrderedSet[string]
+	children   *OrderedSet[string]
 	echoedFrom map[string]bool
+	phase      int64                      // phase of the latest label sent by the node
+	sequences  map[string]int64           // last sequence number sent to each receiver
+	windows    map[string]*sequenceWindow // sequence numbers received from each sender
+	completed  bool
+	messages   int
+}
+
+func init() {
+
+	gob.Register(Result{})
+	gob.Register(Envelope{})
+	gob.Register(Snapshot{})
+	algorithm.Register("bfs", func() algorithm.Algorithm { return new(Node) })
 }
 
 func NodeWith(host Host, id string, neighbors []string) *Node {
@@ -50,16 +94,12 @@ func (node *Node) Set(host Host, id string, neighbors []string) *Node {
 		node.parentID = ""
 		node.treeLevel = -1
 		node.labeled = false
-		node.neighbors = ArrayOfType("string")
-
-		for _, neighborID := range neighbors {
-
-			node.neighbors.AppendUnique(neighborID)
-		}
-
-		node.sendTo = ArrayOfType("string")
-		node.children = ArrayOfType("string")
+		node.neighbors = OrderedSetOf(neighbors...)
+		node.sendTo = OrderedSetOf[string]()
+		node.children = OrderedSetOf[string]()
 		node.echoedFrom = make(map[string]bool)
+		node.sequences = make(map[string]int64)
+		node.windows = make(map[string]*sequenceWindow)
 	}
 	node.once.Do(onceBody)
 	node.guard.Unlock()
@@ -67,6 +107,11 @@ func (node *Node) Set(host Host, id string, neighbors []string) *Node {
 	return node
 }
 
+func (node *Node) Init(environment algorithm.Environment) {
+
+	node.Set(environment.Host, environment.ID, environment.Neighbors)
+}
+
 func (node *Node) IsRoot() bool {
 
 	return strings.Compare(node.parentID, node.id) == 0
@@ -75,9 +120,16 @@ func (node *Node) IsRoot() bool {
 func (node *Node) HandleMessage(sender string, receiver string, command uint8, value interface{}) {
 
 	node.guard.Lock()
-	switch command {
+	defer node.guard.Unlock()
+
+	if command == InitCommand {
+
+		if node.labeled {
+
+			node.drop("duplicate", sender, command)
+			return
+		}
 
-	case InitCommand:
 		node.labeled = true
 		node.parentID = node.id
 		node.treeLevel = 0
@@ -86,24 +138,38 @@ func (node *Node) HandleMessage(sender string, receiver string, command uint8, v
 
 		if node.sendTo.IsEmpty() {
 
-			node.host.SendMessage(node.id, "server", CompleteCommand, nil)
+			node.complete()
 
 		} else {
 
-			for i := 0; i < node.sendTo.Count(); i++ {
-
-				var id = node.sendTo.ElementAtIndex(i).(string)
-				node.echoedFrom[id] = false
-				node.host.SendMessage(node.id, id, LabelCommand, node.treeLevel)
-			}
+			node.phase++
+			node.sendLabels()
 		}
+		return
+	}
+
+	var envelope, valid = value.(Envelope)
+	if !valid {
+
+		Printf("[BFS Algorithm]: Unknown command \"%d\"- do nothing\n", command)
+		return
+	}
+
+	if !node.accept(sender, envelope) {
+
+		node.drop("duplicate", sender, command)
+		return
+	}
+
+	switch command {
 
 	case LabelCommand:
 		if node.labeled == false {
 
 			node.labeled = true
 			node.parentID = sender
-			node.treeLevel = value.(int64) + 1
+			node.treeLevel = envelope.Level + 1
+			node.phase = envelope.Phase
 
 			node.sendTo = node.neighbors.Clone()
 			node.sendTo.Remove(sender)
@@ -111,60 +177,69 @@ func (node *Node) HandleMessage(sender string, receiver string, command uint8, v
 
 			if node.sendTo.IsEmpty() {
 
-				node.host.SendMessage(node.id, node.parentID, EndCommand, nil)
+				node.send(node.parentID, EndCommand, envelope.Phase, 0)
 
 			} else {
 
-				node.host.SendMessage(node.id, node.parentID, KeeponCommand, nil)
+				node.send(node.parentID, KeeponCommand, envelope.Phase, 0)
 			}
 
 		} else {
 
 			if strings.Compare(node.parentID, sender) == 0 {
 
-				for i := 0; i < node.sendTo.Count(); i++ {
+				// the parent forwards every phase exactly once
+				if envelope.Phase <= node.phase {
 
-					var id = node.sendTo.ElementAtIndex(i).(string)
-					node.echoedFrom[id] = false
-					node.host.SendMessage(node.id, id, LabelCommand, node.treeLevel)
+					node.drop("stale", sender, command)
+					return
 				}
+				node.phase = envelope.Phase
+				node.sendLabels()
+
 			} else {
 
-				node.host.SendMessage(node.id, sender, StopCommand, nil)
+				node.send(sender, StopCommand, envelope.Phase, 0)
 			}
 		}
 
 	case KeeponCommand, StopCommand, EndCommand:
 
+		// only the first echo of every neighbor in the current phase counts
+		if envelope.Phase != node.phase || !node.sendTo.Contains(sender) || node.echoedFrom[sender] {
+
+			node.drop("stale", sender, command)
+			return
+		}
+
 		node.echoedFrom[sender] = true
 
 		switch command {
 
 		case KeeponCommand:
-			node.children.AppendUnique(sender)
+			node.children.Insert(sender)
 
 		case StopCommand:
 			node.sendTo.Remove(sender)
 
 		case EndCommand:
-			node.children.AppendUnique(sender)
+			node.children.Insert(sender)
 			node.sendTo.Remove(sender)
 		}
 
 		if node.sendTo.IsEmpty() {
 
 			if node.IsRoot() {
-				node.host.SendMessage(node.id, "server", CompleteCommand, nil)
+				node.complete()
 			} else {
-				node.host.SendMessage(node.id, node.parentID, EndCommand, nil)
+				node.send(node.parentID, EndCommand, node.phase, 0)
 			}
 		} else {
 
 			var everyNodeEchoed = true
 
-			for i := 0; i < node.sendTo.Count(); i++ {
+			for _, id := range node.sendTo.Elements() {
 
-				var id = node.sendTo.ElementAtIndex(i).(string)
 				if node.echoedFrom[id] == false {
 
 					everyNodeEchoed = false
@@ -176,34 +251,142 @@ func (node *Node) HandleMessage(sender string, receiver string, command uint8, v
 
 				if node.IsRoot() {
 
-					for i := 0; i < node.sendTo.Count(); i++ {
+					node.phase++
+					node.sendLabels()
 
-						var id = node.sendTo.ElementAtIndex(i).(string)
-
-						node.echoedFrom[id] = false
-
-						node.host.SendMessage(node.id, id, LabelCommand, node.treeLevel)
-					}
 				} else {
-					node.host.SendMessage(node.id, node.parentID, KeeponCommand, nil)
+					node.send(node.parentID, KeeponCommand, node.phase, 0)
 				}
 			}
 		}
 	default:
 		Printf("[BFS Algorithm]: Unknown command \"%d\"- do nothing\n", command)
 	}
-	node.guard.Unlock()
 }
 
 func (node *Node) Children() []string {
 
 	node.guard.Lock()
-	var children []string
-	for i := 0; i < node.children.Count(); i++ {
-
-		var childID = node.children.ElementAtIndex(i).(string)
-		children = append(children, childID)
-	}
+	var children = node.children.Elements()
 	node.guard.Unlock()
 	return children
 }
+
+func (node *Node) Result() interface{} {
+
+	node.guard.Lock()
+	var result = Result{ID: node.id, ParentID: node.parentID, Level: node.treeLevel, Children: node.children.Elements(), Messages: node.messages}
+	node.guard.Unlock()
+	return result
+}
+
+// Snapshot copies the state of the node, it may be called while the node runs.
+func (node *Node) Snapshot() Snapshot {
+
+	node.guard.Lock()
+	defer node.guard.Unlock()
+
+	var echoedFrom = make(map[string]bool)
+	for id, echoed := range node.echoedFrom {
+
+		echoedFrom[id] = echoed
+	}
+
+	return Snapshot{
+		ID:         node.id,
+		Labeled:    node.labeled,
+		ParentID:   node.parentID,
+		Level:      node.treeLevel,
+		Phase:      node.phase,
+		SendTo:     node.sendTo.Elements(),
+		Children:   node.children.Elements(),
+		EchoedFrom: echoedFrom,
+		Completed:  node.completed,
+		Messages:   node.messages,
+	}
+}
+
+func (node *Node) Status() interface{} {
+
+	return node.Snapshot()
+}
+
+func (snapshot Snapshot) String() string {
+
+	return Sprintf("<ID: %s Labeled: %t Parent: %s Level: %d Phase: %d SendTo: %v Children: %v EchoedFrom: %v Completed: %t Messages: %d>",
+		snapshot.ID, snapshot.Labeled, snapshot.ParentID, snapshot.Level, snapshot.Phase, snapshot.SendTo, snapshot.Children, snapshot.EchoedFrom, snapshot.Completed, snapshot.Messages)
+}
+
+func (result Result) Parent() string {
+
+	return result.ParentID
+}
+
+func (result Result) Distance() float64 {
+
+	return float64(result.Level)
+}
+
+func (result Result) MessageCount() int {
+
+	return result.Messages
+}
+
+// String renders the tree edges of the node as GraphPlot code.
+func (result Result) String() string {
+
+	var description = Sprintf("<ID: %s Parent: %s Level: %d>", result.ID, result.ParentID, result.Level)
+
+	if len(result.Children) > 0 {
+
+		description += "\n[Log] [Tree] [Code-Part]:"
+		for _, childID := range result.Children {
+
+			description += Sprintf("\n%s -> %s, ", result.ID, childID)
+		}
+	}
+	return description
+}
+
+// The following helpers expect the guard to be locked by the caller.
+func (node *Node) sendLabels() {
+
+	for _, id := range node.sendTo.Elements() {
+
+		node.echoedFrom[id] = false
+		node.send(id, LabelCommand, node.phase, node.treeLevel)
+	}
+}
+
+func (node *Node) complete() {
+
+	if !node.completed {
+
+		node.completed = true
+		node.messages++
+		node.host.SendMessage(node.id, "server", CompleteCommand, nil)
+	}
+}
+
+func (node *Node) accept(sender string, envelope Envelope) bool {
+
+	var window, exists = node.windows[sender]
+	if !exists {
+
+		window = &sequenceWindow{next: 1, seen: make(map[int64]bool)}
+		node.windows[sender] = window
+	}
+	return window.accept(envelope.Sequence)
+}
+
+func (node *Node) drop(reason string, sender string, command uint8) {
+
+	Printf("[BFS Algorithm]: dropping %s \"%s\" from %s\n", reason, StringFor(command), sender)
+}
+
+func (node *Node) send(receiver string, command uint8, phase int64, level int64) {
+
+	node.sequences[receiver]++
+	node.messages++
+	node.host.SendMessage(node.id, receiver, command, Envelope{Phase: phase, Sequence: node.sequences[receiver], Level: level})
+}
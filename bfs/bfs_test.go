@@ -0,0 +1,216 @@
+//
+//  bfs_test.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+package bfs
+
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/bfs/command"
+
+import "reflect"
+import "testing"
+
+// sentMessage is a message the node handed to its host.
+type sentMessage struct {
+	receiver string
+	command  uint8
+	envelope Envelope
+}
+
+// fakeHost records every message instead of delivering it.
+type fakeHost struct {
+	sent []sentMessage
+}
+
+func (host *fakeHost) SendMessage(sender string, receiver string, command uint8, value interface{}) {
+
+	var envelope, _ = value.(Envelope)
+	host.sent = append(host.sent, sentMessage{receiver: receiver, command: command, envelope: envelope})
+}
+
+func (host *fakeHost) count(command uint8) int {
+
+	var count = 0
+	for _, message := range host.sent {
+
+		if message.command == command {
+
+			count++
+		}
+	}
+	return count
+}
+
+// delivery is a message handed to the node under test.
+type delivery struct {
+	sender   string
+	command  uint8
+	envelope Envelope
+}
+
+func TestSequenceWindow(t *testing.T) {
+
+	var tests = []struct {
+		name      string
+		sequences []int64
+		accepted  []bool
+		next      int64
+	}{
+		{"in order", []int64{1, 2, 3}, []bool{true, true, true}, 4},
+		{"duplicates", []int64{1, 1, 2, 2, 1}, []bool{true, false, true, false, false}, 3},
+		{"reordered", []int64{3, 1, 2, 3}, []bool{true, true, true, false}, 4},
+		{"gap", []int64{1, 3, 3, 4}, []bool{true, true, false, true}, 2},
+		{"below the start", []int64{0, 1}, []bool{false, true}, 2},
+	}
+
+	for _, test := range tests {
+
+		var window = &sequenceWindow{next: 1, seen: make(map[int64]bool)}
+		var accepted = []bool{}
+		for _, sequence := range test.sequences {
+
+			accepted = append(accepted, window.accept(sequence))
+		}
+
+		if !reflect.DeepEqual(accepted, test.accepted) || window.next != test.next {
+
+			t.Errorf("%s: accepted %v with next %d, expected %v with next %d", test.name, accepted, window.next, test.accepted, test.next)
+		}
+	}
+}
+
+func TestNodeDropsDuplicateAndStaleMessages(t *testing.T) {
+
+	var tests = []struct {
+		name       string
+		id         string
+		neighbors  []string
+		deliveries []delivery
+		sent       []sentMessage // messages the node is expected to send, in order
+	}{
+		{
+			"duplicate init",
+			"a", []string{"b"},
+			[]delivery{{"server", InitCommand, Envelope{}}, {"server", InitCommand, Envelope{}}},
+			[]sentMessage{{"b", LabelCommand, Envelope{1, 1, 0}}},
+		},
+		{
+			"duplicate label",
+			"b", []string{"a", "c"},
+			[]delivery{{"a", LabelCommand, Envelope{1, 1, 0}}, {"a", LabelCommand, Envelope{1, 1, 0}}},
+			[]sentMessage{{"a", KeeponCommand, Envelope{1, 1, 0}}},
+		},
+		{
+			"stale label from the parent",
+			"b", []string{"a", "c"},
+			[]delivery{{"a", LabelCommand, Envelope{1, 1, 0}}, {"a", LabelCommand, Envelope{1, 2, 0}}, {"a", LabelCommand, Envelope{2, 3, 0}}},
+			[]sentMessage{{"a", KeeponCommand, Envelope{1, 1, 0}}, {"c", LabelCommand, Envelope{2, 1, 1}}},
+		},
+		{
+			"echo of an older phase",
+			"a", []string{"b", "c"},
+			[]delivery{
+				{"server", InitCommand, Envelope{}},
+				{"b", KeeponCommand, Envelope{1, 1, 0}},
+				{"c", KeeponCommand, Envelope{1, 1, 0}},
+				{"b", EndCommand, Envelope{1, 2, 0}}, // already echoed in phase 1
+			},
+			[]sentMessage{
+				{"b", LabelCommand, Envelope{1, 1, 0}}, {"c", LabelCommand, Envelope{1, 1, 0}},
+				{"b", LabelCommand, Envelope{2, 2, 0}}, {"c", LabelCommand, Envelope{2, 2, 0}},
+			},
+		},
+		{
+			"echo from a node that is not asked",
+			"a", []string{"b"},
+			[]delivery{{"server", InitCommand, Envelope{}}, {"x", EndCommand, Envelope{1, 1, 0}}},
+			[]sentMessage{{"b", LabelCommand, Envelope{1, 1, 0}}},
+		},
+		{
+			"duplicate end completes once",
+			"a", []string{"b"},
+			[]delivery{
+				{"server", InitCommand, Envelope{}},
+				{"b", EndCommand, Envelope{1, 1, 0}},
+				{"b", EndCommand, Envelope{1, 1, 0}},
+				{"b", EndCommand, Envelope{1, 2, 0}},
+			},
+			[]sentMessage{{"b", LabelCommand, Envelope{1, 1, 0}}, {"server", CompleteCommand, Envelope{}}},
+		},
+	}
+
+	for _, test := range tests {
+
+		var host = new(fakeHost)
+		var node = NodeWith(host, test.id, test.neighbors)
+		for _, message := range test.deliveries {
+
+			node.HandleMessage(message.sender, test.id, message.command, message.envelope)
+		}
+
+		if !reflect.DeepEqual(host.sent, test.sent) {
+
+			t.Errorf("%s: sent %v, expected %v", test.name, host.sent, test.sent)
+		}
+	}
+}
+
+func TestNodeCompletesOnceUnderDuplication(t *testing.T) {
+
+	// a path a - b - c where every message is delivered twice
+	var hosts = map[string]*fakeHost{"a": new(fakeHost), "b": new(fakeHost), "c": new(fakeHost)}
+	var nodes = map[string]*Node{
+		"a": NodeWith(hosts["a"], "a", []string{"b"}),
+		"b": NodeWith(hosts["b"], "b", []string{"a", "c"}),
+		"c": NodeWith(hosts["c"], "c", []string{"b"}),
+	}
+
+	var delivered = map[string]int{}
+	nodes["a"].HandleMessage("server", "a", InitCommand, nil)
+
+	for progress := true; progress; {
+
+		progress = false
+		for _, sender := range []string{"a", "b", "c"} {
+
+			var sent = hosts[sender].sent
+			for ; delivered[sender] < len(sent); delivered[sender]++ {
+
+				var message = sent[delivered[sender]]
+				if message.receiver == "server" {
+					continue
+				}
+
+				for attempt := 0; attempt < 2; attempt++ {
+
+					nodes[message.receiver].HandleMessage(sender, message.receiver, message.command, message.envelope)
+				}
+				progress = true
+			}
+		}
+	}
+
+	if count := hosts["a"].count(CompleteCommand); count != 1 {
+
+		t.Errorf("root sent %d complete messages, expected 1", count)
+	}
+
+	var expected = map[string]Result{
+		"a": {ID: "a", ParentID: "a", Level: 0, Children: []string{"b"}},
+		"b": {ID: "b", ParentID: "a", Level: 1, Children: []string{"c"}},
+		"c": {ID: "c", ParentID: "b", Level: 2, Children: []string{}},
+	}
+
+	for id, node := range nodes {
+
+		var result = node.Result().(Result)
+		result.Messages = 0
+		if !reflect.DeepEqual(result, expected[id]) {
+
+			t.Errorf("node %s has result %v, expected %v", id, result, expected[id])
+		}
+	}
+}
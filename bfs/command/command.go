@@ -6,6 +6,7 @@
 //  All rights reserved.
 //
 
+// Package command defines the message commands exchanged by server, clients and nodes.
 package command
 
 const /* Message Command constants */ (
@@ -18,8 +19,72 @@ const /* Message Command constants */ (
 	StopCommand          uint8 = iota
 	CompleteCommand      uint8 = iota
 	FinalCommand         uint8 = iota
+	ResultCommand        uint8 = iota
+	RelayCommand         uint8 = iota
+	ReadyCommand         uint8 = iota
+	StatusCommand        uint8 = iota
+	StatusReplyCommand   uint8 = iota
 )
 
+const /* Asynchronous BFS command constants */ (
+	AsyncLabelCommand uint8 = iota + 32
+	AsyncAckCommand   uint8 = iota + 32
+)
+
+const /* DFS command constants */ (
+	DiscoverCommand   uint8 = iota + 48
+	VisitedCommand    uint8 = iota + 48
+	VisitedAckCommand uint8 = iota + 48
+	ReturnCommand     uint8 = iota + 48
+)
+
+const /* Echo command constants */ (
+	ExploreCommand uint8 = iota + 64
+	EchoCommand    uint8 = iota + 64
+)
+
+const /* Leader election command constants */ (
+	ElectCommand    uint8 = iota + 80
+	WaveCommand     uint8 = iota + 80
+	WaveEchoCommand uint8 = iota + 80
+	LeaderCommand   uint8 = iota + 80
+)
+
+const /* Shortest path command constants */ (
+	DistanceCommand    uint8 = iota + 96
+	DistanceAckCommand uint8 = iota + 96
+)
+
+const /* Reachability command constants */ (
+	ReachCommand    uint8 = iota + 112
+	ReachAckCommand uint8 = iota + 112
+)
+
+const /* Minimum spanning tree (GHS) command constants */ (
+	ConnectCommand    uint8 = iota + 128
+	InitiateCommand   uint8 = iota + 128
+	TestCommand       uint8 = iota + 128
+	AcceptCommand     uint8 = iota + 128
+	RejectCommand     uint8 = iota + 128
+	ReportCommand     uint8 = iota + 128
+	ChangeRootCommand uint8 = iota + 128
+)
+
+const /* Reliable delivery command constants */ (
+	AckCommand uint8 = iota + 144
+)
+
+// IsRuntimeCommand reports whether the command is handled by the client
+// runtime itself to wire the overlay and collect the results.
+func IsRuntimeCommand(command uint8) bool {
+
+	switch command {
+	case NewNeighborCommand, StopListeningCommand, ReadyCommand, FinalCommand, ResultCommand, StatusCommand, StatusReplyCommand:
+		return true
+	}
+	return false
+}
+
 func StringFor(command uint8) string {
 
 	switch command {
@@ -41,6 +106,64 @@ func StringFor(command uint8) string {
 		return "Complete"
 	case FinalCommand:
 		return "Final"
+	case ResultCommand:
+		return "Result"
+	case RelayCommand:
+		return "Relay"
+	case ReadyCommand:
+		return "Ready"
+	case StatusCommand:
+		return "Status"
+	case StatusReplyCommand:
+		return "Status Reply"
+	case AsyncLabelCommand:
+		return "Async Label"
+	case AsyncAckCommand:
+		return "Async Ack"
+	case DiscoverCommand:
+		return "Discover"
+	case VisitedCommand:
+		return "Visited"
+	case VisitedAckCommand:
+		return "Visited Ack"
+	case ReturnCommand:
+		return "Return"
+	case ExploreCommand:
+		return "Explore"
+	case EchoCommand:
+		return "Echo"
+	case ElectCommand:
+		return "Elect"
+	case WaveCommand:
+		return "Wave"
+	case WaveEchoCommand:
+		return "Wave Echo"
+	case LeaderCommand:
+		return "Leader"
+	case DistanceCommand:
+		return "Distance"
+	case DistanceAckCommand:
+		return "Distance Ack"
+	case ReachCommand:
+		return "Reach"
+	case ReachAckCommand:
+		return "Reach Ack"
+	case ConnectCommand:
+		return "Connect"
+	case InitiateCommand:
+		return "Initiate"
+	case TestCommand:
+		return "Test"
+	case AcceptCommand:
+		return "Accept"
+	case RejectCommand:
+		return "Reject"
+	case ReportCommand:
+		return "Report"
+	case ChangeRootCommand:
+		return "Change Root"
+	case AckCommand:
+		return "Ack"
 	}
 	return "Unknown Command"
 }
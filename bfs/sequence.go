@@ -0,0 +1,45 @@
+//
+//  sequence.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+package bfs
+
+// Envelope is the value of LabelCommand, KeeponCommand, StopCommand and
+// EndCommand. Phase is the round of the root the message belongs to, every
+// label the root sends out starts a new one, and the echoes carry the phase of
+// the label they answer. Sequence numbers the messages of one sender to one
+// receiver, starting at 1, so a receiver recognizes duplicates.
+type Envelope struct {
+	Phase    int64
+	Sequence int64
+	Level    int64 // level of the sender, only used by labels
+}
+
+// sequenceWindow remembers which sequence numbers of one sender arrived. It
+// keeps the numbers above the first gap only, so it stays small as long as
+// messages are not lost.
+type sequenceWindow struct {
+	next int64 // every number below arrived
+	seen map[int64]bool
+}
+
+// accept returns false if the sequence number arrived before.
+func (window *sequenceWindow) accept(sequence int64) bool {
+
+	if sequence < window.next || window.seen[sequence] {
+
+		return false
+	}
+	window.seen[sequence] = true
+
+	for window.seen[window.next] {
+
+		delete(window.seen, window.next)
+		window.next++
+	}
+	return true
+}
@@ -0,0 +1,252 @@
+//
+//  chaos.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+// Package chaos injects network faults into the send paths of the server and
+// the clients: delays, reordering, duplicates, drops and connection resets.
+package chaos
+
+import . "fmt"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/message"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/bfs/command"
+
+import "math/rand"
+import "strconv"
+import "strings"
+import "sync"
+import "time"
+
+// holdTime is the longest a reordered message waits for a later one to overtake it.
+const holdTime = 100 * time.Millisecond
+
+// Config describes which faults to inject. The probabilities apply to every
+// message independently, the zero value injects nothing.
+type Config struct {
+	Seed      int64
+	Delay     time.Duration // every message is delayed by a random duration up to Delay
+	Reorder   float64       // probability that a message is overtaken by the next one on its link
+	Duplicate float64       // probability that a message is delivered twice
+	Drop      float64       // probability that a message is lost
+	Reset     float64       // probability that the connection is reset instead of sending
+}
+
+// Injector applies the faults of a config, decisions are drawn from one
+// random source seeded with Config.Seed. A nil injector sends everything
+// unchanged.
+type Injector struct {
+	guard  sync.Mutex
+	config Config
+	random *rand.Rand
+	held   map[string]*heldMessage // message per link waiting to be overtaken
+}
+
+type heldMessage struct {
+	once    sync.Once
+	message Message
+	deliver func(Message)
+}
+
+// ParseConfig reads a comma separated list of key=value pairs, e.g.
+// "seed=7,delay=20ms,reorder=0.1,duplicate=0.05,drop=0.01,reset=0.001".
+// The seed is not mixed with anything, every process started with the same
+// specification draws the same sequence of decisions.
+func ParseConfig(specification string) (Config, error) {
+
+	var config = Config{Seed: 1}
+	if len(strings.TrimSpace(specification)) == 0 {
+
+		return Config{}, nil
+	}
+
+	for _, pair := range strings.Split(specification, ",") {
+
+		var key, value, found = strings.Cut(strings.TrimSpace(pair), "=")
+		if !found {
+
+			return Config{}, Errorf("chaos option %q is not key=value", pair)
+		}
+
+		var parseError error
+		switch key {
+
+		case "seed":
+			config.Seed, parseError = strconv.ParseInt(value, 10, 64)
+
+		case "delay":
+			config.Delay, parseError = time.ParseDuration(value)
+
+		case "reorder":
+			config.Reorder, parseError = parseProbability(value)
+
+		case "duplicate":
+			config.Duplicate, parseError = parseProbability(value)
+
+		case "drop":
+			config.Drop, parseError = parseProbability(value)
+
+		case "reset":
+			config.Reset, parseError = parseProbability(value)
+
+		default:
+			return Config{}, Errorf("unknown chaos option %q", key)
+		}
+
+		if parseError != nil {
+
+			return Config{}, Errorf("chaos option %q: %v", pair, parseError)
+		}
+	}
+	return config, nil
+}
+
+func parseProbability(value string) (float64, error) {
+
+	var probability, parseError = strconv.ParseFloat(value, 64)
+	if parseError == nil && (probability < 0 || probability > 1) {
+
+		parseError = Errorf("%g is no probability", probability)
+	}
+	return probability, parseError
+}
+
+// Enabled reports whether the config injects any fault.
+func (config Config) Enabled() bool {
+
+	return config.Delay > 0 || config.Reorder > 0 || config.Duplicate > 0 || config.Drop > 0 || config.Reset > 0
+}
+
+// InjectorWith returns nil if the config injects no fault.
+func InjectorWith(config Config) *Injector {
+
+	if !config.Enabled() {
+
+		return nil
+	}
+
+	var injector = new(Injector)
+	injector.config = config
+	injector.random = rand.New(rand.NewSource(config.Seed))
+	injector.held = make(map[string]*heldMessage)
+	return injector
+}
+
+// Send passes the message of the link to deliver, possibly late, twice or not
+// at all, or calls reset instead. Runtime commands that wire the overlay and
+// collect the results are never touched, and messages from or to the server
+// are only delayed or reordered: nothing on that link recovers from a lost,
+// duplicated or reset message. deliver may be called from another routine.
+func (injector *Injector) Send(link string, message Message, deliver func(Message), reset func()) {
+
+	if injector == nil || IsRuntimeCommand(message.Command) {
+
+		deliver(message)
+		return
+	}
+
+	injector.guard.Lock()
+	var config = injector.config
+	var resetting = injector.random.Float64() < config.Reset
+	var dropping = injector.random.Float64() < config.Drop
+	var copies = 1
+	if injector.random.Float64() < config.Duplicate {
+
+		copies = 2
+	}
+	var delays = make([]time.Duration, copies)
+	for index := range delays {
+
+		if config.Delay > 0 {
+
+			delays[index] = time.Duration(injector.random.Int63n(int64(config.Delay) + 1))
+		}
+	}
+	var holding = injector.random.Float64() < config.Reorder
+
+	if message.Sender == "server" || message.Receiver == "server" {
+
+		resetting, dropping, delays = false, false, delays[:1]
+	}
+
+	// a message held back earlier on this link is released after this one
+	var overtaken = injector.held[link]
+	delete(injector.held, link)
+
+	if holding && overtaken == nil && !resetting && !dropping {
+
+		var held = &heldMessage{message: message, deliver: deliver}
+		injector.held[link] = held
+		injector.guard.Unlock()
+
+		injector.log("holding back", message)
+		time.AfterFunc(holdTime, func() {
+
+			injector.guard.Lock()
+			if injector.held[link] == held {
+
+				delete(injector.held, link)
+			}
+			injector.guard.Unlock()
+			held.release()
+		})
+		return
+	}
+	injector.guard.Unlock()
+
+	if resetting {
+
+		injector.log("resetting the connection instead of sending", message)
+		reset()
+
+	} else if dropping {
+
+		injector.log("dropping", message)
+
+	} else {
+
+		if len(delays) > 1 {
+
+			injector.log("duplicating", message)
+		}
+
+		for _, delay := range delays {
+
+			deliverAfter(delay, message, deliver)
+		}
+	}
+
+	if overtaken != nil {
+
+		injector.log("releasing overtaken", overtaken.message)
+		overtaken.release()
+	}
+}
+
+func (held *heldMessage) release() {
+
+	held.once.Do(func() {
+
+		held.deliver(held.message)
+	})
+}
+
+func deliverAfter(delay time.Duration, message Message, deliver func(Message)) {
+
+	if delay == 0 {
+
+		deliver(message)
+		return
+	}
+	time.AfterFunc(delay, func() {
+
+		deliver(message)
+	})
+}
+
+func (injector *Injector) log(fault string, message Message) {
+
+	Printf("[Log] [Chaos]: %s <%s> from <%s> to <%s>\n", fault, StringFor(message.Command), message.Sender, message.Receiver)
+}
@@ -0,0 +1,94 @@
+//
+//  chaos_test.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+package chaos
+
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/message"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/bfs/command"
+
+import "strings"
+import "testing"
+import "time"
+
+func TestParseConfig(t *testing.T) {
+
+	var tests = []struct {
+		name          string
+		specification string
+		expected      Config
+		err           string
+	}{
+		{"empty", "", Config{}, ""},
+		{"blank", "  ", Config{}, ""},
+		{"default seed", "drop=0.5", Config{Seed: 1, Drop: 0.5}, ""},
+		{"every option", "seed=7, delay=20ms,reorder=0.1,duplicate=0.05,drop=0.01,reset=0", Config{Seed: 7, Delay: 20 * time.Millisecond, Reorder: 0.1, Duplicate: 0.05, Drop: 0.01}, ""},
+		{"bounds", "drop=0,reset=1", Config{Seed: 1, Reset: 1}, ""},
+		{"not key=value", "drop", Config{}, "\"drop\" is not key=value"},
+		{"empty option", "drop=0.1,", Config{}, "is not key=value"},
+		{"unknown option", "loss=0.1", Config{}, "unknown chaos option \"loss\""},
+		{"probability above one", "drop=1.5", Config{}, "1.5 is no probability"},
+		{"negative probability", "reorder=-0.1", Config{}, "-0.1 is no probability"},
+		{"invalid probability", "duplicate=often", Config{}, "chaos option \"duplicate=often\""},
+		{"invalid duration", "delay=20", Config{}, "chaos option \"delay=20\""},
+		{"invalid seed", "seed=x", Config{}, "chaos option \"seed=x\""},
+	}
+
+	for _, test := range tests {
+
+		var config, err = ParseConfig(test.specification)
+		if test.err != "" {
+
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+
+				t.Errorf("%s: got error %v, expected %q", test.name, err, test.err)
+			}
+			continue
+		}
+
+		if err != nil {
+
+			t.Errorf("%s: unexpected error %v", test.name, err)
+
+		} else if config != test.expected {
+
+			t.Errorf("%s: got %+v, expected %+v", test.name, config, test.expected)
+		}
+	}
+}
+
+func TestSendExemptsTheServerLink(t *testing.T) {
+
+	var tests = []struct {
+		name      string
+		config    Config
+		message   Message
+		delivered int
+		resets    int
+	}{
+		{"nil injector", Config{}, Message{Sender: "a", Receiver: "b", Command: LabelCommand}, 1, 0},
+		{"drop between clients", Config{Seed: 1, Drop: 1}, Message{Sender: "a", Receiver: "b", Command: LabelCommand}, 0, 0},
+		{"drop to the server", Config{Seed: 1, Drop: 1}, Message{Sender: "a", Receiver: "server", Command: LabelCommand}, 1, 0},
+		{"drop from the server", Config{Seed: 1, Drop: 1}, Message{Sender: "server", Receiver: "a", Command: InitCommand}, 1, 0},
+		{"duplicate between clients", Config{Seed: 1, Duplicate: 1}, Message{Sender: "a", Receiver: "b", Command: LabelCommand}, 2, 0},
+		{"duplicate to the server", Config{Seed: 1, Duplicate: 1}, Message{Sender: "a", Receiver: "server", Command: CompleteCommand}, 1, 0},
+		{"reset between clients", Config{Seed: 1, Reset: 1}, Message{Sender: "a", Receiver: "b", Command: LabelCommand}, 0, 1},
+		{"reset to the server", Config{Seed: 1, Reset: 1}, Message{Sender: "a", Receiver: "server", Command: CompleteCommand}, 1, 0},
+		{"runtime command", Config{Seed: 1, Drop: 1}, Message{Sender: "a", Receiver: "b", Command: NewNeighborCommand}, 1, 0},
+	}
+
+	for _, test := range tests {
+
+		var delivered, resets = 0, 0
+		InjectorWith(test.config).Send("link", test.message, func(Message) { delivered++ }, func() { resets++ })
+
+		if delivered != test.delivered || resets != test.resets {
+
+			t.Errorf("%s: delivered %d times with %d resets, expected %d with %d", test.name, delivered, resets, test.delivered, test.resets)
+		}
+	}
+}
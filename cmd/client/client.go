@@ -0,0 +1,545 @@
+//
+//  client.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+package main
+
+import . "fmt"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/array"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/helper"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/message"
+import . "encoding/gob"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/bfs/command"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/identification"
+
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/algorithm"
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/chaos"
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/election"
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/reliable"
+
+// algorithms register themselves when imported
+import _ "github.com/DevAndArtist/Distributed-BFS-in-Go/bfs"
+import _ "github.com/DevAndArtist/Distributed-BFS-in-Go/dfs"
+import _ "github.com/DevAndArtist/Distributed-BFS-in-Go/sssp"
+import _ "github.com/DevAndArtist/Distributed-BFS-in-Go/reach"
+import _ "github.com/DevAndArtist/Distributed-BFS-in-Go/ghs"
+import _ "github.com/DevAndArtist/Distributed-BFS-in-Go/echo"
+
+import "io"
+import "os"
+import "net"
+import "flag"
+import "strconv"
+import "sync/atomic"
+import "time"
+
+type Client struct {
+	ID               string
+	Listener         net.Listener
+	ServerConnection net.Conn
+	ServerEncoder    *Encoder
+	Neighbors        *TypedArray[*Neighbor]
+	Value            *float64 // nil uses the node's degree
+	AlgorithmName    chan string
+	Algorithms       *algorithm.Instances // one algorithm per traversal instance
+	Election         *election.Node
+	ElectionMailbox  *algorithm.Mailbox // keeps the election messages of every link in order
+	Chaos            *chaos.Injector    // nil unless faults are injected
+	Wired            atomic.Bool        // the overlay is complete, only known neighbors may reconnect
+	MessagePipe      chan Message
+	Complete         chan bool
+}
+
+type Neighbor struct {
+	ID       string
+	Address  string // set if this client dialed the neighbor and redials it
+	Weight   float64
+	Directed bool
+	Outgoing bool // messages may be sent to the neighbor
+	Link     *reliable.Link
+}
+
+// redialAttempts is how often a lost neighbor connection is dialed again
+// before the neighbor is considered gone, e.g. because it finished.
+const redialAttempts = 5
+
+func init() {
+	// register gob types
+	Register(Identification{})
+}
+
+func main() {
+
+	var value = flag.String("value", "", "per-node value used by aggregating algorithms (defaults to the node's degree)")
+	var chaosSpecification = flag.String("chaos", "", "inject faults into sent messages, e.g. \"seed=7,delay=20ms,reorder=0.1,duplicate=0.05,drop=0.01,reset=0\"")
+	flag.Parse()
+
+	Println("\nStarting client ...")
+
+	var client = new(Client)
+
+	var chaosConfig, chaosError = chaos.ParseConfig(*chaosSpecification)
+	HandleError(chaosError, func() {
+
+		Println(chaosError)
+		os.Exit(2)
+	})
+	client.Chaos = chaos.InjectorWith(chaosConfig)
+
+	if len(*value) > 0 {
+
+		var parsedValue, parseError = strconv.ParseFloat(*value, 64)
+		HandleError(parseError, func() {
+
+			Println(parseError)
+			os.Exit(2)
+		})
+		client.Value = &parsedValue
+	}
+
+	client.ID = GenerateID()
+	client.Neighbors = TypedArrayOf[*Neighbor]()
+	client.AlgorithmName = make(chan string, 1)
+	client.MessagePipe = make(chan Message)
+	client.Complete = make(chan bool)
+
+	Printf("[Log]: clients <ID: %s>\n", client.ID)
+
+	go client.HandleMessages()
+
+	var listener, listenerError = net.Listen("tcp", "localhost:0")
+	HandleError(listenerError, func() {
+
+		Println(listenerError)
+		os.Exit(1)
+	})
+	client.Listener = listener
+
+	// the listener stays open, neighbors that lost their connection redial it
+	var listenForNewClients = func() {
+
+		for {
+			Println("[Log] [Go]: wait for neighbor client")
+			// warte auf eingehende verbindung
+			var clientConnection, connectionError = listener.Accept()
+			HandleError(connectionError, func() {
+
+				Println(connectionError)
+				os.Exit(10)
+			})
+			go client.AcceptNeighbor(clientConnection)
+		}
+	}
+	go listenForNewClients()
+	go client.SetUpAlgorithms()
+
+	//===========================================================================================
+	//===========================================================================================
+	//===========================================================================================
+	Println("[Log]: client will dial the server")
+	var connectionToServer, connectionError = net.Dial("tcp", "localhost:8081")
+	HandleError(connectionError, func() {
+
+		Println(connectionError)
+		os.Exit(30)
+	})
+	client.ServerConnection = connectionToServer
+	client.ServerEncoder = NewEncoder(connectionToServer)
+	Println("[Log]: connection to server established")
+	Println("[Log]: client will send its identification to the server")
+	// sende die ID und Rückrufaddresse für clients an den server
+	var identificationMessage = Identification{ID: client.ID, Address: listener.Addr().String()}
+	var encodingError = client.ServerEncoder.Encode(identificationMessage)
+	HandleError(encodingError, func() {
+
+		Println(encodingError)
+		os.Exit(40)
+	})
+	go client.ListenTo(&client.ServerConnection)
+
+	// warte bis der client mit allem fertig ist
+	<-client.Complete
+
+	for _, neighbor := range client.Neighbors.Snapshot() {
+
+		neighbor.Link.Close()
+	}
+
+	os.Exit(0)
+}
+
+func (client *Client) AcceptNeighbor(connection net.Conn) {
+
+	// warte auf die übermittelte ID und das Gewicht der Verbindung
+	var tempDecoder = NewDecoder(connection)
+	var identification Identification
+	var decodingError = tempDecoder.Decode(&identification)
+	if decodingError != nil {
+
+		Printf("[Log] [Go]: neighbor handshake failed: %v\n", decodingError)
+		connection.Close()
+		return
+	}
+
+	var neighbor, known = client.Neighbors.Find(func(aNeighbor *Neighbor) bool {
+
+		return EqualStrings(aNeighbor.ID, identification.ID)
+	})
+
+	if known && identification.Epoch == 0 {
+
+		Printf("[Log] [Go]: rejecting a second link from client <%s>\n", identification.ID)
+		connection.Close()
+		return
+
+	} else if known {
+
+		Printf("[Log] [Go]: client <%s> reconnected (epoch %d)\n", identification.ID, identification.Epoch)
+
+	} else if client.Wired.Load() || identification.Epoch > 0 {
+
+		Printf("[Log] [Go]: rejecting unknown client <%s>, the overlay is complete\n", identification.ID)
+		connection.Close()
+		return
+
+	} else {
+
+		// erstelle eine neue instanz
+		neighbor = new(Neighbor)
+		neighbor.ID = identification.ID
+		neighbor.Weight = identification.Weight
+		neighbor.Directed = identification.Directed
+		neighbor.Outgoing = !identification.Directed
+		neighbor.Link = reliable.LinkBetween(client.ID, neighbor.ID, client.Chaos)
+		Printf("[Log] [Go]: new client <%s> accepted\n", identification.ID)
+		// füge den neuen nachbar in ein array
+		client.Neighbors.Append(neighbor)
+	}
+
+	if !neighbor.Link.Attach(connection, NewEncoder(connection), identification.Epoch) {
+
+		Printf("[Log] [Go]: rejecting stale connection of client <%s> with epoch %d\n", identification.ID, identification.Epoch)
+		connection.Close()
+		return
+	}
+	// starte eine go routine um den client zu bearbeiten
+	go client.ListenToNeighbor(neighbor, connection, tempDecoder)
+}
+
+// SetUpAlgorithms creates the algorithm instances once the server stopped the
+// wiring and told which algorithm to run.
+func (client *Client) SetUpAlgorithms() {
+
+	var name = <-client.AlgorithmName
+	client.Wired.Store(true)
+
+	Println("[Log] [Go]: safe to set node neighbors")
+
+	var neighbors []string
+	var weights = make(map[string]float64)
+	for _, neighbor := range client.Neighbors.Snapshot() {
+
+		// a directed link into this client is not visible to the algorithm
+		if neighbor.Outgoing {
+
+			neighbors = append(neighbors, neighbor.ID)
+			weights[neighbor.ID] = neighbor.Weight
+		}
+	}
+
+	var nodeValue = float64(len(neighbors))
+	if client.Value != nil {
+
+		nodeValue = *client.Value
+	}
+
+	var environment = algorithm.Environment{Host: client, ID: client.ID, Neighbors: neighbors, Weights: weights, Value: nodeValue}
+	var algorithms, algorithmError = algorithm.InstancesOf(name, environment, client)
+	HandleError(algorithmError, func() {
+
+		Println(algorithmError)
+		os.Exit(80)
+	})
+	Printf("[Log] [Go]: client will run algorithm <%s>\n", name)
+	client.Algorithms = algorithms
+
+	client.Election = election.NodeWith(environment, func() {
+
+		// the elected leader starts the algorithm instead of the server
+		Println("[Log] [Go]: client was elected as leader")
+		client.Algorithms.HandleMessage("", client.ID, client.ID, InitCommand, nil)
+	})
+	client.ElectionMailbox = algorithm.MailboxFor(client.Election)
+
+	// tell the server how many links the client has, the run may start now
+	client.SendMessage(client.ID, "server", ReadyCommand, client.Neighbors.Count())
+}
+
+// ListenToNeighbor reads from one connection to a neighbor until it fails,
+// then the client that dialed the neighbor dials it again.
+func (client *Client) ListenToNeighbor(neighbor *Neighbor, connection net.Conn, decoder *Decoder) {
+
+	for {
+		var message Message
+		var decodingError = decoder.Decode(&message)
+		if decodingError != nil {
+
+			if !neighbor.Link.Detach(connection) {
+
+				return // replaced by a newer connection
+			}
+
+			if decodingError == io.EOF {
+
+				Printf("[Log] [Go]: neighbor client <%s> closed the connection\n", neighbor.ID)
+
+			} else {
+
+				Printf("[Log] [Go]: lost connection to neighbor client <%s>: %v\n", neighbor.ID, decodingError)
+			}
+
+			if len(neighbor.Address) > 0 {
+
+				client.RedialNeighbor(neighbor)
+			}
+			return
+		}
+
+		for _, ready := range neighbor.Link.Receive(message) {
+
+			client.MessagePipe <- ready
+		}
+	}
+}
+
+func (client *Client) ListenTo(connection *net.Conn) {
+
+	var decoder = NewDecoder(*connection)
+
+	for {
+		Println("[Log] [GO]: wait for incoming messages")
+		var message Message
+		var decodingError = decoder.Decode(&message)
+		HandleError(decodingError, func() {
+
+			Println(decodingError)
+			os.Exit(50)
+		})
+		client.MessagePipe <- message
+	}
+}
+
+func (client *Client) HandleMessages() {
+
+	for {
+
+		var message = <-client.MessagePipe
+
+		Println("\n[Log] [Async]: new message")
+		Printf("\tSender:   %s\n\tReceiver: %s\n\tCommand:  %s\n\tValue:\t  %v\n\tInstance: %s\n\n", message.Sender, message.Receiver, StringFor(message.Command), message.Value, message.Instance)
+
+		if EqualStrings(message.Sender, "server") {
+
+			switch message.Command {
+
+			case NewNeighborCommand:
+				var identification = message.Value.(Identification)
+				go client.DialNeighbor(identification, "tcp")
+
+			case StopListeningCommand:
+				// the server tells which algorithm to run once the overlay is complete
+				var name, _ = message.Value.(string)
+				if len(name) == 0 {
+					name = "bfs"
+				}
+				client.AlgorithmName <- name
+
+			case StatusCommand:
+				go client.ReportStatus()
+
+			case ElectCommand:
+				client.ElectionMailbox.Post(message.Sender, message.Receiver, message.Command, message.Value)
+
+			case FinalCommand:
+
+				// the server lists every instance it started, including those that never reached this client
+				var instances, _ = message.Value.([]string)
+				for _, instance := range instances {
+
+					client.Algorithms.Instance(instance)
+				}
+
+				// report one result per traversal instance
+				for _, instanceResult := range client.Algorithms.Results() {
+
+					Printf("[Log] [Result]: %v\n", instanceResult.Result)
+
+					// the message pipe is handled by this very routine, so encode directly
+					var resultMessage = Message{Sender: client.ID, Receiver: "server", Command: ResultCommand, Value: instanceResult.Result, Instance: instanceResult.Instance}
+					var encodingError = client.ServerEncoder.Encode(resultMessage)
+					HandleError(encodingError, func() {
+
+						Println(encodingError)
+						os.Exit(110)
+					})
+				}
+
+				client.Complete <- true
+
+			default:
+				// everything else from the server (e.g. InitCommand) is meant for the algorithm
+				client.Algorithms.HandleMessage(message.Instance, message.Sender, message.Receiver, message.Command, message.Value)
+			}
+
+		} else {
+
+			if EqualStrings(message.Receiver, "server") {
+
+				// e.g. the complete command of the algorithm
+				client.Chaos.Send("server", message, func(message Message) {
+
+					var encodingError = client.ServerEncoder.Encode(message)
+					HandleError(encodingError, func() {
+
+						Println(encodingError)
+						os.Exit(120)
+					})
+					Println("[Log] [Go]: message send to server")
+
+				}, func() {
+
+					client.ServerConnection.Close()
+				})
+
+			} else if EqualStrings(message.Receiver, client.ID) && election.IsElectionCommand(message.Command) {
+
+				client.ElectionMailbox.Post(message.Sender, message.Receiver, message.Command, message.Value)
+
+			} else if EqualStrings(message.Receiver, client.ID) {
+
+				client.Algorithms.HandleMessage(message.Instance, message.Sender, message.Receiver, message.Command, message.Value)
+
+			} else {
+
+				var neighbor, found = client.Neighbors.Find(func(aNeighbor *Neighbor) bool {
+
+					return EqualStrings(aNeighbor.ID, message.Receiver)
+				})
+
+				if !found {
+
+					Println("[Log] [Go]: SOMETHING IS REALLY BROKEN :(")
+					os.Exit(130)
+				}
+
+				if !neighbor.Outgoing {
+
+					Printf("[Log] [Go]: link to client <ID: %s> is directed towards this client\n", neighbor.ID)
+					os.Exit(150)
+				}
+
+				// retransmitted until the neighbor acknowledged it, also after a reconnect
+				neighbor.Link.Send(message)
+				Println("[Log] [Go]: message send to neighbor client")
+			}
+		}
+	}
+}
+
+// ReportStatus answers StatusCommand with the state of every algorithm
+// instance. It runs on its own routine, an instance may wait for the message
+// pipe while it holds its state.
+func (client *Client) ReportStatus() {
+
+	var statuses = []algorithm.InstanceStatus{}
+	if client.Algorithms != nil {
+
+		statuses = client.Algorithms.Statuses()
+	}
+	client.SendMessage(client.ID, "server", StatusReplyCommand, statuses)
+}
+
+func (client *Client) SendMessage(sender string, receiver string, command uint8, value interface{}) {
+
+	client.SendInstanceMessage("", sender, receiver, command, value)
+}
+
+func (client *Client) SendInstanceMessage(instance string, sender string, receiver string, command uint8, value interface{}) {
+
+	client.MessagePipe <- Message{Sender: sender, Receiver: receiver, Command: command, Value: value, Instance: instance}
+}
+
+func (client *Client) DialNeighbor(identification Identification, network string) {
+
+	Printf("[Log] [Go]: client will dial another client <ID: %s Address: %s>\n", identification.ID, identification.Address)
+	var connection, connectionError = net.Dial(network, identification.Address)
+	HandleError(connectionError, func() {
+
+		Println(connectionError)
+		os.Exit(60)
+	})
+	Println("[Log] [Go]: successfully connected")
+
+	var neighbor = new(Neighbor)
+	neighbor.ID = identification.ID
+	neighbor.Address = identification.Address
+	neighbor.Weight = identification.Weight
+	neighbor.Directed = identification.Directed
+	neighbor.Outgoing = true
+	neighbor.Link = reliable.LinkBetween(client.ID, neighbor.ID, client.Chaos)
+
+	var handshakeError = client.Handshake(neighbor, connection, 0)
+	HandleError(handshakeError, func() {
+
+		Println(handshakeError)
+		os.Exit(70)
+	})
+	client.Neighbors.Append(neighbor)
+
+	go client.ListenToNeighbor(neighbor, connection, NewDecoder(connection))
+}
+
+// RedialNeighbor connects to a neighbor again after its connection was lost.
+// It gives up quietly if the neighbor does not answer, e.g. because it exited.
+func (client *Client) RedialNeighbor(neighbor *Neighbor) {
+
+	for attempt := 1; attempt <= redialAttempts; attempt++ {
+
+		time.Sleep(time.Duration(attempt) * 100 * time.Millisecond)
+
+		Printf("[Log] [Go]: client will redial <ID: %s> (attempt %d)\n", neighbor.ID, attempt)
+		var connection, connectionError = net.Dial("tcp", neighbor.Address)
+		if connectionError != nil {
+			continue
+		}
+
+		if handshakeError := client.Handshake(neighbor, connection, neighbor.Link.Epoch()+1); handshakeError != nil {
+
+			connection.Close()
+			continue
+		}
+
+		go client.ListenToNeighbor(neighbor, connection, NewDecoder(connection))
+		return
+	}
+	Printf("[Log] [Go]: giving up on neighbor client <ID: %s>\n", neighbor.ID)
+}
+
+// Handshake sends the own ID and the link weight to the dialed neighbor and
+// attaches the connection to the neighbor's link.
+func (client *Client) Handshake(neighbor *Neighbor, connection net.Conn, epoch uint64) error {
+
+	Println("[Log] [Go]: send own ID and the link weight to the connected client")
+	var encoder = NewEncoder(connection)
+	var encodingError = encoder.Encode(Identification{ID: client.ID, Weight: neighbor.Weight, Directed: neighbor.Directed, Epoch: epoch})
+	if encodingError != nil {
+
+		return encodingError
+	}
+	neighbor.Link.Attach(connection, encoder, epoch)
+	return nil
+}
@@ -0,0 +1,92 @@
+//
+//  cluster.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+package main
+
+import . "fmt"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/cluster"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/helper"
+
+import "os"
+import "flag"
+import "time"
+import "strings"
+import "path/filepath"
+
+func main() {
+
+	var clientCount = flag.Int("n", 3, "number of clients to start (at least 3)")
+	var timeout = flag.Duration("timeout", 2*time.Minute, "maximum time the whole traversal may take")
+	var serverPath = flag.String("server", "", "path to a prebuilt server binary (built from "+ServerPackage+" if empty)")
+	var clientPath = flag.String("client", "", "path to a prebuilt client binary (built from "+ClientPackage+" if empty)")
+	var serverArgs = flag.String("server-args", "", "additional server flags, e.g. \"-algorithm bfs\"")
+	var clientArgs = flag.String("client-args", "", "additional flags passed to every client")
+	flag.Parse()
+
+	Println("\nStarting cluster ...")
+
+	if *clientCount < 3 {
+
+		Printf("[Log]: the server needs at least 3 clients, got %d\n", *clientCount)
+		os.Exit(2)
+	}
+
+	var cluster = ClusterWith(*serverPath, *clientPath, *clientCount, *timeout)
+	cluster.ServerArgs = strings.Fields(*serverArgs)
+	cluster.ClientArgs = strings.Fields(*clientArgs)
+
+	var buildDirectory = ""
+
+	if len(cluster.ServerPath) == 0 || len(cluster.ClientPath) == 0 {
+
+		var directory, directoryError = os.MkdirTemp("", "bfs-cluster")
+		HandleError(directoryError, func() {
+
+			Println(directoryError)
+			os.Exit(1)
+		})
+		buildDirectory = directory
+
+		var build = func(importPath string, name string) string {
+
+			var output = filepath.Join(buildDirectory, name)
+			var buildError = Build(importPath, output)
+			HandleError(buildError, func() {
+
+				Printf("[Log]: %v\n", buildError)
+				os.RemoveAll(buildDirectory)
+				os.Exit(1)
+			})
+			return output
+		}
+
+		if len(cluster.ServerPath) == 0 {
+
+			cluster.ServerPath = build(ServerPackage, "server")
+		}
+
+		if len(cluster.ClientPath) == 0 {
+
+			cluster.ClientPath = build(ClientPackage, "client")
+		}
+	}
+
+	var success = cluster.Run()
+
+	if len(buildDirectory) > 0 {
+
+		os.RemoveAll(buildDirectory)
+	}
+
+	if !success {
+
+		Println("[Log]: cluster run failed")
+		os.Exit(1)
+	}
+	Println("[Log]: cluster run completed without errors")
+}
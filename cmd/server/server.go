@@ -0,0 +1,1006 @@
+//
+//  server.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+package main
+
+import . "fmt"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/graph"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/array"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/helper"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/message"
+import . "encoding/gob"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/bfs/command"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/identification"
+
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/algorithm"
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/chaos"
+
+// algorithms register themselves (and their gob result types) when imported
+import _ "github.com/DevAndArtist/Distributed-BFS-in-Go/bfs"
+import _ "github.com/DevAndArtist/Distributed-BFS-in-Go/dfs"
+import _ "github.com/DevAndArtist/Distributed-BFS-in-Go/sssp"
+import _ "github.com/DevAndArtist/Distributed-BFS-in-Go/reach"
+
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/echo"
+
+import "net"
+import "errors"
+import "time"
+import "math/rand"
+import "strconv"
+import "strings"
+import "sort"
+import "flag"
+import "os"
+
+import "sync"
+
+type Server struct {
+	Clients       *TypedArray[*Client]
+	AlgorithmName string
+	Aggregate     string
+	Elect         bool            // clients elect the root instead of the server
+	Leaders       []string        // IDs of elected leaders, one per connected component
+	Runs          map[string]*Run // traversal instances by ID
+	Instances     []string        // IDs of the runs in start order
+	Completed     int             // number of runs that reported completion
+	Complete      chan bool
+	MessagePipe   chan Message
+	ResultPipe    chan Message
+	Graph         *Graph
+	VertexIDs     []string // client ID of every graph vertex
+	StartTime     time.Time
+	Chaos         *chaos.Injector // nil unless faults are injected
+	Members       []*Client       // every client that joined, by vertex, also after it was lost
+	ReadyClients  int             // number of clients that reported they are set up
+	Wired         chan bool       // closed once every client is ready
+	Traversed     chan bool       // closed once every run completed
+	StatusPipe    chan Message    // answers to StatusCommand
+	FinalTimeout  time.Duration
+	guard         sync.Mutex // guards the progress of the clients and runs
+}
+
+// Run is one traversal instance of the algorithm, started at its own root.
+type Run struct {
+	Instance string
+	Root     Vertex
+	Outcome  interface{} // value of the root's complete message
+	Pending  int         // complete messages still expected, one per elected leader
+	Results  map[string]interface{}
+	Duration time.Duration
+	Complete bool
+}
+
+type Client struct {
+	Identification Identification
+	Address        string
+	Connection     net.Conn
+	Encoder        *Encoder
+	Decoder        *Decoder
+	Vertex         int // position in the join order, the vertex of the client once every client joined
+
+	// what the server last saw of the client, to report the ones that hold up a phase
+	Ready        bool
+	Lost         bool
+	Received     int // messages received after the identification
+	LastCommand  uint8
+	LastInstance string
+	LastSeen     time.Time
+}
+
+var seed *rand.Rand
+
+func init() {
+	// initialize global time based seed
+	seed = rand.New(rand.NewSource(time.Now().UTC().UnixNano()))
+
+	// register for gob
+	Register(Identification{})
+}
+
+func main() {
+
+	Println("\nStarting server ...")
+
+	var algorithmName = flag.String("algorithm", "bfs", "algorithm to run ("+strings.Join(algorithm.Names(), ", ")+")")
+	var aggregate = flag.String("aggregate", "all", "aggregate printed by the echo algorithm ("+strings.Join(echo.Aggregates, ", ")+" or all)")
+	var elect = flag.Bool("elect", false, "let the clients elect the root with the largest ID instead of choosing it")
+	var sources = flag.Int("sources", 1, "number of concurrent runs started at different roots")
+	var maxWeight = flag.Int("weights", 1, "largest random edge weight, 1 creates an unweighted graph")
+	var graphPath = flag.String("graph", "", "read the graph from a file with one \"from to [weight]\" edge per line")
+	var savePath = flag.String("save-graph", "", "write the graph to a file to run it again with -graph")
+	var directed = flag.Bool("directed", false, "give every random edge a direction, messages only flow along it")
+	var degree = flag.Int("degree", 0, "average degree of a sparse random graph, 0 creates a dense one")
+	var chaosSpecification = flag.String("chaos", "", "inject faults into sent messages, e.g. \"seed=7,delay=20ms,reorder=0.1,duplicate=0.05,drop=0.01,reset=0\"")
+	var joinTimeout = flag.Duration("join-timeout", 2*time.Minute, "time for all clients to join, 0 waits forever")
+	var wiringTimeout = flag.Duration("wiring-timeout", 30*time.Second, "time for the clients to wire up the overlay, 0 waits forever")
+	var traversalTimeout = flag.Duration("traversal-timeout", 5*time.Minute, "time for all runs to complete, 0 waits forever")
+	var finalTimeout = flag.Duration("final-timeout", 5*time.Second, "time for the clients to report their results, 0 waits forever")
+	var readyFD = flag.Int("ready-fd", 0, "inherited file descriptor to write \"ready\" to once clients may dial, 0 disables it")
+	flag.Parse()
+
+	if !algorithm.IsRegistered(*algorithmName) {
+
+		Printf("[Log]: unknown algorithm <%s>\n", *algorithmName)
+		os.Exit(3)
+	}
+
+	if _, known := (echo.Aggregate{}).Value(*aggregate); !known && *aggregate != "all" {
+
+		Printf("[Log]: unknown aggregate <%s>\n", *aggregate)
+		os.Exit(3)
+	}
+
+	if *sources < 1 || (*sources > 1 && *elect) {
+
+		Printf("[Log]: cannot start %d concurrent runs (elect: %t)\n", *sources, *elect)
+		os.Exit(3)
+	}
+
+	var arguments = flag.Args()
+
+	var maxClientNumber = 3 // default value is 3
+
+	if len(arguments) >= 1 {
+
+		var parsedNumber, parseError = strconv.ParseUint(arguments[0], 10, 32)
+		HandleError(parseError, func() {
+
+			Println(parseError)
+			os.Exit(1)
+		})
+
+		if int(parsedNumber) > maxClientNumber {
+
+			maxClientNumber = int(parsedNumber)
+		}
+	}
+
+	if *sources > maxClientNumber {
+
+		Printf("[Log]: cannot start %d concurrent runs on %d clients\n", *sources, maxClientNumber)
+		os.Exit(3)
+	}
+
+	var chaosConfig, chaosError = chaos.ParseConfig(*chaosSpecification)
+	if chaosError != nil {
+
+		Printf("[Log]: %v\n", chaosError)
+		os.Exit(3)
+	}
+
+	if *directed && *elect {
+
+		Println("[Log]: leader election needs undirected links")
+		os.Exit(3)
+	}
+
+	if *maxWeight < 1 {
+
+		Printf("[Log]: invalid largest edge weight %d\n", *maxWeight)
+		os.Exit(3)
+	}
+
+	var graph *Graph
+	if len(*graphPath) > 0 {
+
+		graph = ReadGraphFile(*graphPath)
+		var vertices = graph.Vertices()
+		if last := vertices[len(vertices)-1]; int(last) >= maxClientNumber {
+
+			Printf("[Log]: graph <%s> needs %d clients\n", *graphPath, last+1)
+			os.Exit(3)
+		}
+	}
+
+	if (*directed || (graph != nil && graph.IsDirected())) && !SupportsDirectedLinks(*algorithmName) {
+
+		Printf("[Log]: algorithm <%s> needs undirected links\n", *algorithmName)
+		os.Exit(3)
+	}
+
+	// start listening for clients
+	var listener, listenerError = net.Listen("tcp", "localhost:8081")
+	HandleError(listenerError, func() {
+
+		Println(listenerError)
+		os.Exit(2)
+	})
+
+	// listening for clients now
+	Printf("[Log]: server will accept exact %d clients\n", maxClientNumber)
+
+	// tell a supervisor like cmd/cluster that the clients may dial now
+	if *readyFD > 0 {
+
+		var ready = os.NewFile(uintptr(*readyFD), "ready")
+		var _, readyError = Fprintln(ready, "ready")
+		HandleError(readyError, func() {
+
+			Printf("[Log]: could not signal readiness on fd %d: %v\n", *readyFD, readyError)
+		})
+		ready.Close()
+	}
+
+	// now we are safe to create and initialize the server instance
+	var server = new(Server)
+	server.Clients = TypedArrayOf[*Client]()
+	server.AlgorithmName = *algorithmName
+	server.Aggregate = *aggregate
+	server.Elect = *elect
+	server.Complete = make(chan bool)
+	server.MessagePipe = make(chan Message)
+	server.ResultPipe = make(chan Message, maxClientNumber*(*sources))
+	server.Runs = make(map[string]*Run)
+	server.Chaos = chaos.InjectorWith(chaosConfig)
+	server.Wired = make(chan bool)
+	server.Traversed = make(chan bool)
+	server.StatusPipe = make(chan Message, maxClientNumber)
+	server.FinalTimeout = *finalTimeout
+
+	go server.HandleMessages()
+
+	var waitGroup = new(sync.WaitGroup)
+	var joinDeadline = Deadline(*joinTimeout)
+	if *joinTimeout > 0 {
+
+		listener.(*net.TCPListener).SetDeadline(time.Now().Add(*joinTimeout))
+	}
+
+	// wait for all needed clients to join the network
+	for server.Clients.Count() < maxClientNumber {
+
+		Printf("[Log]: waiting for %d more clients\n", (maxClientNumber - server.Clients.Count()))
+
+		// wait and accept new clients
+		var newConnection, acceptingError = listener.Accept()
+		if errors.Is(acceptingError, os.ErrDeadlineExceeded) {
+
+			Printf("[Log] [Timeout]: only %d of %d clients joined\n", server.Clients.Count(), maxClientNumber)
+			server.ReportTimeout("join", *joinTimeout, server.Lagging(func(client *Client) bool {
+
+				return len(client.Identification.ID) == 0
+			}))
+		}
+		HandleError(acceptingError, nil)
+
+		waitGroup.Add(1)
+
+		// when a new connection is established we are safe to create a new client instance
+		var client = new(Client)
+		client.Connection = newConnection
+		client.Encoder = NewEncoder(newConnection)
+		client.Decoder = NewDecoder(newConnection)
+		client.Vertex = server.Clients.Count()
+		// save the pointer to the client instance for later communication
+		server.Clients.Append(client)
+
+		Printf("[Log]: client at <Index: %d> joined the network\n", (server.Clients.Count() - 1))
+
+		// handle the client on a different routine
+		go server.ListenToClient(client, waitGroup)
+	}
+
+	// wait until every client told the clients its ID
+	var identified = make(chan bool)
+	go func() {
+
+		waitGroup.Wait()
+		close(identified)
+	}()
+
+	select {
+	case <-identified:
+	case <-joinDeadline:
+		server.ReportTimeout("join", *joinTimeout, server.Lagging(func(client *Client) bool {
+
+			return len(client.Identification.ID) == 0
+		}))
+	}
+	// Clients shrinks when a client is lost, so clients are looked up by
+	// vertex in Members from now on
+	server.guard.Lock()
+	server.Members = server.Clients.Snapshot()
+	for vertex, client := range server.Members {
+
+		client.Vertex = vertex
+	}
+	server.guard.Unlock()
+
+	// stop listening for other connections
+	listener.Close()
+
+	if graph == nil {
+
+		Println("[Log]: calculating random graph")
+
+		// create random graph
+		if *degree > 0 {
+
+			graph = CreateRandomSparseGraph(maxClientNumber, *degree, *maxWeight)
+
+		} else {
+
+			graph = CreateRandomWeightedGraph(maxClientNumber, *maxWeight)
+		}
+
+		if *directed {
+
+			graph = graph.WithRandomDirections()
+		}
+	}
+
+	// every client is a vertex, even without any edge
+	for vertex := 0; vertex < maxClientNumber; vertex++ {
+
+		graph.AddVertex(Vertex(vertex))
+	}
+	LogGraph(graph)
+
+	if len(*savePath) > 0 {
+
+		WriteGraphFile(*savePath, graph)
+	}
+
+	server.Graph = graph
+	for _, client := range server.Members {
+
+		server.VertexIDs = append(server.VertexIDs, client.Identification.ID)
+	}
+
+	var clients = server.Members
+	for _, edge := range graph.EdgeList() {
+
+		var client_1 = clients[edge.From]
+		var client_2 = clients[edge.To]
+
+		// client_1 dials client_2 and passes the weight on to it
+		var identification = client_2.Identification
+		identification.Weight = float64(edge.Weight)
+		identification.Directed = edge.Directed
+		server.MessagePipe <- Message{Sender: "server", Receiver: client_1.Identification.ID, Command: NewNeighborCommand, Value: identification}
+	}
+
+	time.Sleep(time.Second * 5)
+
+	for _, client := range server.Clients.Snapshot() {
+
+		server.MessagePipe <- Message{Sender: "server", Receiver: client.Identification.ID, Command: StopListeningCommand, Value: server.AlgorithmName}
+	}
+
+	// every client reports ReadyCommand once its algorithm is set up
+	select {
+	case <-server.Wired:
+	case <-Deadline(*wiringTimeout):
+		server.ReportTimeout("wiring", *wiringTimeout, server.Lagging(func(client *Client) bool {
+
+			return !client.Ready
+		}))
+	}
+
+	if server.Elect {
+
+		// the elected leader initiates the algorithm on its own, the server only observes,
+		// the root of the run is the sender of the complete message, every
+		// component elects its own leader that completes its own traversal
+		server.AddRun("", 0)
+		server.Runs[""].Pending = len(graph.ConnectedComponents())
+
+	} else if *sources == 1 {
+
+		// send init message to a random node
+		server.AddRun("", graph.EdgeList()[0].From)
+
+	} else {
+
+		// every run gets its own instance ID, so the nodes keep independent state per run
+		var roots = seed.Perm(maxClientNumber)[:*sources]
+		sort.Ints(roots)
+		for _, vertex := range roots {
+
+			server.AddRun(Sprintf("source-%d", vertex), Vertex(vertex))
+		}
+	}
+
+	server.PrintGraphStats()
+
+	server.StartTime = time.Now()
+
+	if server.Elect {
+
+		for _, client := range server.Clients.Snapshot() {
+
+			server.MessagePipe <- Message{Sender: "server", Receiver: client.Identification.ID, Command: ElectCommand, Value: nil}
+		}
+
+	} else {
+
+		for _, instance := range server.Instances {
+
+			var run = server.Runs[instance]
+			var startClient = server.Members[run.Root]
+			server.MessagePipe <- Message{Sender: "server", Receiver: startClient.Identification.ID, Command: InitCommand, Value: nil, Instance: instance}
+		}
+	}
+
+	select {
+	case <-server.Traversed:
+	case <-Deadline(*traversalTimeout):
+		server.ReportTimeout("traversal", *traversalTimeout, server.TraversalLagging())
+	}
+
+	// wait until the results are collected and verified
+	// on a different go routine
+	if !<-server.Complete {
+
+		Println("[Log]: server will terminate, the run failed")
+		os.Exit(4)
+	}
+	Println("[Log]: server will terminate without errors")
+	os.Exit(0)
+}
+
+func (server *Server) HandleMessages() {
+
+	for {
+
+		var message = <-server.MessagePipe
+
+		Println("\n[Log] [Go]: new message")
+		Printf("\tSender:   %s\n\tReceiver: %s\n\tCommand:  %s\n\tValue:\t  %v\n\tInstance: %s\n", message.Sender, message.Receiver, StringFor(message.Command), message.Value, message.Instance)
+
+		if EqualStrings(message.Receiver, "server") && message.Command == ResultCommand {
+
+			server.ResultPipe <- message
+
+		} else if EqualStrings(message.Receiver, "server") && message.Command == RelayCommand {
+
+			// e.g. an acknowledgement against the direction of a link
+			var relay = message.Value.(Relay)
+			server.SendToClient(Message{Sender: message.Sender, Receiver: relay.Receiver, Command: relay.Command, Value: relay.Value, Instance: message.Instance})
+
+		} else if EqualStrings(message.Receiver, "server") && message.Command == ReadyCommand {
+
+			Printf("[Log] [Go]: client <ID: %s> is ready with %v links\n", message.Sender, message.Value)
+			server.guard.Lock()
+			for _, client := range server.Members {
+
+				if EqualStrings(client.Identification.ID, message.Sender) && !client.Ready {
+
+					client.Ready = true
+					server.ReadyClients += 1
+				}
+			}
+			if server.ReadyClients == len(server.Members) {
+
+				close(server.Wired)
+			}
+			server.guard.Unlock()
+
+		} else if EqualStrings(message.Receiver, "server") && message.Command == StatusReplyCommand {
+
+			select {
+			case server.StatusPipe <- message:
+			default:
+				Printf("[Log] [Go]: dropping unexpected status of client <ID: %s>\n", message.Sender)
+			}
+
+		} else if EqualStrings(message.Receiver, "server") && message.Command == LeaderCommand {
+
+			Printf("[Log] [Election]: client <ID: %s> was elected as leader\n", message.Sender)
+			server.Leaders = append(server.Leaders, message.Sender)
+
+		} else if EqualStrings(message.Receiver, "server") {
+
+			server.guard.Lock()
+			var run, known = server.Runs[message.Instance]
+			if !known || run.Complete {
+
+				server.guard.Unlock()
+				Printf("[Log] [Go]: ignoring completion of instance <%s>\n", message.Instance)
+				continue
+			}
+			// the sender of the complete message is the root of the run
+			for vertex, id := range server.VertexIDs {
+
+				if EqualStrings(id, message.Sender) {
+
+					run.Root = Vertex(vertex)
+				}
+			}
+			run.Duration = time.Since(server.StartTime)
+			run.Outcome = message.Value
+
+			run.Pending -= 1
+			if run.Pending > 0 {
+
+				server.guard.Unlock()
+				Printf("[Log] [Go]: instance <%s> waits for %d more leaders to complete\n", message.Instance, run.Pending)
+				continue
+			}
+			Printf("[Log] [Go]: server is happy about completion of instance <%s>\n", message.Instance)
+			run.Complete = true
+
+			server.Completed += 1
+			if server.Completed == len(server.Runs) {
+
+				close(server.Traversed)
+				go server.FinalStep()
+			}
+			server.guard.Unlock()
+
+		} else {
+
+			server.SendToClient(message)
+		}
+	}
+}
+
+func (server *Server) SendToClient(message Message) {
+
+	var client, found = server.Clients.Find(func(aClient *Client) bool {
+
+		return EqualStrings(aClient.Identification.ID, message.Receiver)
+	})
+
+	if found {
+
+		Printf("[Log] [Go]: server will send message to client <ID: %s>\n\n", client.Identification.ID)
+
+		server.Chaos.Send(client.Identification.ID, message, func(message Message) {
+
+			var encodingError = client.Encoder.Encode(message)
+			HandleError(encodingError, func() {
+
+				server.RemoveClient(client)
+				Println(encodingError)
+				os.Exit(10)
+			})
+
+		}, func() {
+
+			server.RemoveClient(client)
+		})
+	}
+}
+
+// SupportsDirectedLinks reports whether an algorithm can run on directed links.
+func SupportsDirectedLinks(name string) bool {
+
+	var instance, _ = algorithm.New(name)
+	var directed, ok = instance.(algorithm.DirectedAlgorithm)
+	return ok && directed.SupportsDirectedLinks()
+}
+
+func (server *Server) FinalStep() {
+
+	var clients = server.Clients.Snapshot()
+
+	for _, client := range clients {
+
+		server.MessagePipe <- Message{Sender: "server", Receiver: client.Identification.ID, Command: FinalCommand, Value: server.Instances}
+	}
+
+	// collect the results, but give up on clients that take longer than the final timeout
+	var timeout = Deadline(server.FinalTimeout)
+	var expected = len(clients) * len(server.Runs)
+
+	for received := 0; received < expected; {
+
+		select {
+		case message := <-server.ResultPipe:
+			if run, known := server.Runs[message.Instance]; known {
+
+				run.Results[message.Sender] = message.Value
+				received += 1
+			}
+
+		case <-timeout:
+			Printf("[Log]: received only %d of %d results\n", received, expected)
+			server.ReportTimeout("final", server.FinalTimeout, server.Lagging(func(client *Client) bool {
+
+				for _, run := range server.Runs {
+
+					if _, reported := run.Results[client.Identification.ID]; !reported {
+
+						return true
+					}
+				}
+				return false
+			}))
+		}
+	}
+
+	var valid = true
+	for _, instance := range server.Instances {
+
+		var run = server.Runs[instance]
+
+		Printf("[Log]: results of all clients for instance <%s>\n", instance)
+		var messages = 0
+		for _, client := range clients {
+
+			var result = run.Results[client.Identification.ID]
+			Printf("[Log] [Result]: %v\n", result)
+
+			if counter, ok := result.(algorithm.MessageCounter); ok {
+
+				messages += counter.MessageCount()
+			}
+		}
+		Printf("[Log] [Stats]: algorithm <%s> instance <%s> completed after %v with %d messages\n", server.AlgorithmName, instance, run.Duration, messages)
+
+		if aggregate, ok := run.Outcome.(echo.Aggregate); ok {
+
+			server.PrintAggregate(aggregate)
+		}
+
+		var verificationError = server.Verify(run)
+		if verificationError != nil {
+
+			Printf("[Log] [Verify]: result of <%s> instance <%s> is invalid: %v\n", server.AlgorithmName, instance, verificationError)
+			valid = false
+		}
+	}
+
+	if len(server.Runs) > 1 {
+
+		server.PrintDistances()
+	}
+	server.Complete <- valid
+}
+
+func ReadGraphFile(path string) *Graph {
+
+	var file, openError = os.Open(path)
+	HandleError(openError, func() {
+
+		Println(openError)
+		os.Exit(3)
+	})
+	defer file.Close()
+
+	var graph, readError = ReadGraph(file)
+	HandleError(readError, func() {
+
+		Println(readError)
+		os.Exit(3)
+	})
+
+	if graph.EdgeCount() == 0 {
+
+		Printf("[Log]: graph <%s> has no edges\n", path)
+		os.Exit(3)
+	}
+	return graph
+}
+
+func WriteGraphFile(path string, graph *Graph) {
+
+	var file, createError = os.Create(path)
+	HandleError(createError, func() {
+
+		Println(createError)
+		os.Exit(3)
+	})
+	defer file.Close()
+
+	var writeError = WriteGraph(file, graph)
+	HandleError(writeError, func() {
+
+		Println(writeError)
+		os.Exit(3)
+	})
+	Printf("[Log]: graph was written to <%s>\n", path)
+}
+
+// PrintGraphStats summarizes the graph before the runs start, e.g. the root's
+// eccentricity bounds the number of BFS phases.
+func (server *Server) PrintGraphStats() {
+
+	var stats = server.Graph.Stats()
+
+	var diameter = Sprintf("%d", stats.Diameter)
+	if !stats.DiameterExact {
+
+		diameter = "at least " + diameter
+	}
+
+	Printf("[Log] [Graph] [Stats]: %d vertices, %d edges (directed: %t), density %.3f\n", stats.Vertices, stats.Edges, stats.Directed, stats.Density)
+	Printf("[Log] [Graph] [Stats]: degree min %d, max %d, mean %.2f\n", stats.MinDegree, stats.MaxDegree, stats.MeanDegree)
+	Printf("[Log] [Graph] [Stats]: degree distribution (degree: vertices) %s\n", stats.DegreeDistribution())
+	Printf("[Log] [Graph] [Stats]: %d connected components, diameter %s\n", stats.Components, diameter)
+
+	if server.Elect {
+
+		Println("[Log] [Graph] [Stats]: the root will be elected by the clients")
+		return
+	}
+
+	for _, instance := range server.Instances {
+
+		var root = server.Runs[instance].Root
+		Printf("[Log] [Graph] [Stats]: root %d of instance <%s> has eccentricity %d\n", root, instance, server.Graph.Eccentricity(root))
+	}
+}
+
+// AddRun registers a traversal instance started at the given root.
+func (server *Server) AddRun(instance string, root Vertex) {
+
+	server.Runs[instance] = &Run{Instance: instance, Root: root, Pending: 1, Results: make(map[string]interface{})}
+	server.Instances = append(server.Instances, instance)
+}
+
+// PrintDistances prints the distance of every vertex from the root of every
+// run, which for runs from all vertices is the all-pairs distance matrix.
+func (server *Server) PrintDistances() {
+
+	Println("[Log] [Distances]: rows are roots, columns are vertices, - is unreachable")
+
+	var header = "\t"
+	for vertex := range server.VertexIDs {
+
+		header += Sprintf("\t%d", vertex)
+	}
+	Println(header)
+
+	for _, instance := range server.Instances {
+
+		var run = server.Runs[instance]
+		var row = Sprintf("\t%d", run.Root)
+		for _, id := range server.VertexIDs {
+
+			var result, ok = run.Results[id].(algorithm.DistanceResult)
+			if !ok {
+
+				Printf("[Log] [Distances]: algorithm <%s> reports no distances\n", server.AlgorithmName)
+				return
+			}
+
+			if tree, ok := result.(algorithm.TreeResult); ok && len(tree.Parent()) == 0 {
+
+				row += "\t-"
+				continue
+			}
+			row += Sprintf("\t%g", result.Distance())
+		}
+		Println(row)
+	}
+}
+
+func (server *Server) PrintAggregate(aggregate echo.Aggregate) {
+
+	if server.Aggregate == "all" {
+
+		Printf("[Log] [Aggregate]: %v\n", aggregate)
+		return
+	}
+
+	var value, _ = aggregate.Value(server.Aggregate)
+	Printf("[Log] [Aggregate]: %s = %g\n", server.Aggregate, value)
+}
+
+func (server *Server) ListenToClient(client *Client, waitGroup *sync.WaitGroup) {
+
+	var identification Identification
+	var decodingError = client.Decoder.Decode(&identification)
+	HandleError(decodingError, nil)
+
+	server.guard.Lock()
+	client.Identification = identification
+	client.LastSeen = time.Now()
+	server.guard.Unlock()
+
+	waitGroup.Done()
+
+	server.guard.Lock()
+	Printf("[Log] [Go]: client at <Index: %d> is <ID: %s>\n", client.Vertex, client.Identification.ID)
+	server.guard.Unlock()
+
+	for run := true; run; {
+
+		var message Message
+		var decodingError = client.Decoder.Decode(&message)
+		HandleError(decodingError, func() {
+
+			server.guard.Lock()
+			Printf("[Log] [Go]: lost connection to client <ID: %s> at <Index: %d> \n", client.Identification.ID, client.Vertex)
+			client.Lost = true
+			server.guard.Unlock()
+			server.RemoveClient(client)
+			run = false
+		})
+
+		if run == false {
+			break
+		}
+
+		server.guard.Lock()
+		client.Received += 1
+		client.LastCommand = message.Command
+		client.LastInstance = message.Instance
+		client.LastSeen = time.Now()
+		server.guard.Unlock()
+
+		server.MessagePipe <- message
+	}
+}
+
+func (server *Server) RemoveClient(client *Client) {
+
+	client.Connection.Close()
+
+	server.Clients.Each(func(_ int, aClient *Client) bool {
+
+		if aClient == client {
+
+			server.Clients.Remove(aClient)
+			Println("[Log] [Go]: client was removed")
+			return false
+		}
+		return true
+	})
+}
+
+// Deadline returns a channel that fires after the timeout, or never for 0.
+func Deadline(timeout time.Duration) <-chan time.Time {
+
+	if timeout <= 0 {
+
+		return nil
+	}
+	return time.After(timeout)
+}
+
+// JoinedClients returns every client that joined by vertex, before the join
+// phase is over the ones that joined so far.
+func (server *Server) JoinedClients() []*Client {
+
+	if server.Members == nil {
+
+		return server.Clients.Snapshot()
+	}
+	return server.Members
+}
+
+// Lagging returns the clients that joined and hold up the current phase.
+func (server *Server) Lagging(isLagging func(client *Client) bool) []*Client {
+
+	var members = server.JoinedClients()
+
+	server.guard.Lock()
+	defer server.guard.Unlock()
+
+	var lagging []*Client
+	for _, client := range members {
+
+		if isLagging(client) {
+
+			lagging = append(lagging, client)
+		}
+	}
+	return lagging
+}
+
+// TraversalLagging returns the clients that hold up an unfinished run: its
+// root, or every client while the root is not elected yet, and every client
+// the server lost the connection to.
+func (server *Server) TraversalLagging() []*Client {
+
+	server.guard.Lock()
+	var roots = make(map[Vertex]bool)
+	var unknownRoot = false
+	for _, run := range server.Runs {
+
+		if !run.Complete {
+
+			roots[run.Root] = true
+			unknownRoot = unknownRoot || server.Elect
+		}
+	}
+	server.guard.Unlock()
+
+	var vertices = make(map[*Client]Vertex)
+	for vertex, client := range server.Members {
+
+		vertices[client] = Vertex(vertex)
+	}
+
+	return server.Lagging(func(client *Client) bool {
+
+		return unknownRoot || roots[vertices[client]] || client.Lost
+	})
+}
+
+// ReportTimeout prints what the server last saw of every lagging client and
+// terminates the server.
+func (server *Server) ReportTimeout(phase string, timeout time.Duration, lagging []*Client) {
+
+	Printf("[Log] [Timeout]: %s phase did not finish within %v, %d of the joined clients are lagging\n", phase, timeout, len(lagging))
+
+	server.guard.Lock()
+	for _, client := range lagging {
+
+		var seen = "nothing received yet"
+		if !client.LastSeen.IsZero() {
+
+			seen = Sprintf("last seen %v ago", time.Since(client.LastSeen).Round(time.Millisecond))
+		}
+		if client.Received > 0 {
+
+			seen += Sprintf(" sending <%s> of instance <%s>", StringFor(client.LastCommand), client.LastInstance)
+		}
+
+		Printf("[Log] [Timeout]: client at <Index: %d> <ID: %s> (ready: %t, lost: %t): %s\n", client.Vertex, client.Identification.ID, client.Ready, client.Lost, seen)
+	}
+	server.guard.Unlock()
+
+	server.DumpStatus(statusWait)
+
+	Println("[Log]: server will terminate, a phase timed out")
+	os.Exit(5)
+}
+
+// statusWait is how long DumpStatus waits for the clients to answer.
+const statusWait = 2 * time.Second
+
+// DumpStatus asks every client for the state of its algorithm instances, e.g.
+// the labels, parents and pending echoes of every bfs.Node, and prints the
+// answers that arrive within the wait.
+func (server *Server) DumpStatus(wait time.Duration) {
+
+	if server.Members == nil {
+
+		return // the clients are not wired up yet
+	}
+
+	for _, client := range server.Clients.Snapshot() {
+
+		server.MessagePipe <- Message{Sender: "server", Receiver: client.Identification.ID, Command: StatusCommand}
+	}
+
+	var replies = make(map[string][]algorithm.InstanceStatus)
+	var timeout = time.After(wait)
+
+	for collecting := true; collecting && len(replies) < server.Clients.Count(); {
+
+		select {
+		case message := <-server.StatusPipe:
+			var statuses, _ = message.Value.([]algorithm.InstanceStatus)
+			replies[message.Sender] = statuses
+
+		case <-timeout:
+			collecting = false
+		}
+	}
+
+	for vertex, client := range server.Members {
+
+		var statuses, answered = replies[client.Identification.ID]
+		if !answered {
+
+			Printf("[Log] [Status]: client at <Index: %d> <ID: %s> did not answer\n", vertex, client.Identification.ID)
+			continue
+		}
+
+		if len(statuses) == 0 {
+
+			Printf("[Log] [Status]: client at <Index: %d> <ID: %s> has no algorithm yet\n", vertex, client.Identification.ID)
+		}
+
+		for _, status := range statuses {
+
+			var state interface{} = status.Status
+			if state == nil {
+
+				state = Sprintf("algorithm <%s> reports no status", server.AlgorithmName)
+			}
+			Printf("[Log] [Status]: client at <Index: %d> instance <%s> (%d pending messages): %v\n", vertex, status.Instance, status.Pending, state)
+		}
+	}
+}
@@ -0,0 +1,428 @@
+//
+//  verify.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+package main
+
+import . "fmt"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/graph"
+
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/algorithm"
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/echo"
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/ghs"
+
+import "math"
+
+// Verifier checks the collected results of a run against the wired graph.
+type Verifier func(server *Server, run *Run) error
+
+var verifiers = map[string]Verifier{
+	"bfs":       VerifyBFSLevels,
+	"async-bfs": VerifyBFSLevels,
+	"dfs":       VerifyDFSTree,
+	"echo":      VerifyEchoAggregate,
+	"sssp":      VerifyShortestPaths,
+	"reach":     VerifyReachability,
+	"ghs":       VerifySpanningTree,
+}
+
+// Verify runs the verifier of the current algorithm on a run, if there is one.
+func (server *Server) Verify(run *Run) error {
+
+	var verifier, exists = verifiers[server.AlgorithmName]
+	if !exists {
+
+		return nil
+	}
+	var verificationError = verifier(server, run)
+	if verificationError == nil {
+
+		Printf("[Log] [Verify]: result of <%s> instance <%s> is valid\n", server.AlgorithmName, run.Instance)
+	}
+	return verificationError
+}
+
+// VerifyDFSTree checks that the parents reported by the nodes form a tree
+// rooted at the start vertex, spanning its connected component, in which every
+// non-tree edge connects an ancestor with one of its descendants.
+func VerifyDFSTree(server *Server, run *Run) error {
+
+	var parents, parentsError = server.TreeParents(run)
+	if parentsError != nil {
+
+		return parentsError
+	}
+
+	// the vertices reachable from the root must be exactly the visited ones,
+	// unless every component elected its own leader that started a traversal
+	var reachable = server.ReachableFrom(run.Root)
+
+	for vertex := range server.VertexIDs {
+
+		var _, visited = parents[Vertex(vertex)]
+		if visited != reachable[Vertex(vertex)] && !(visited && server.Elect) {
+
+			return Errorf("vertex %d is reachable: %t, but visited: %t", vertex, reachable[Vertex(vertex)], visited)
+		}
+	}
+
+	if parents[run.Root] != run.Root {
+
+		return Errorf("root %d reports parent %d", run.Root, parents[run.Root])
+	}
+
+	// ancestors[v] contains every vertex on the path from v to the root
+	var ancestors = make(map[Vertex]map[Vertex]bool)
+	for vertex := range reachable {
+
+		ancestors[vertex] = map[Vertex]bool{}
+		for current, steps := vertex, 0; current != run.Root; current, steps = parents[current], steps+1 {
+
+			if steps > len(parents) {
+
+				return Errorf("parents of vertex %d contain a cycle", vertex)
+			}
+
+			if !server.Graph.HasEdge(current, parents[current]) {
+
+				return Errorf("tree edge %d -> %d is not part of the graph", parents[current], current)
+			}
+			ancestors[vertex][parents[current]] = true
+		}
+	}
+
+	for _, edge := range server.Graph.EdgeList() {
+
+		if !reachable[edge.From] {
+			continue
+		}
+
+		if !ancestors[edge.From][edge.To] && !ancestors[edge.To][edge.From] {
+
+			return Errorf("edge %d -> %d connects two different subtrees", edge.From, edge.To)
+		}
+	}
+	return nil
+}
+
+// VerifyBFSLevels compares the level of every node with its hop distance from
+// the root and checks that its parent is a neighbor one level closer.
+func VerifyBFSLevels(server *Server, run *Run) error {
+
+	var parents, parentsError = server.TreeParents(run)
+	if parentsError != nil {
+
+		return parentsError
+	}
+
+	var expected = server.Graph.HopDistances(run.Root)
+	for vertex, id := range server.VertexIDs {
+
+		var parent, visited = parents[Vertex(vertex)]
+		var distance, reachable = expected[Vertex(vertex)]
+		if visited && !reachable && server.Elect {
+			continue // visited by the leader of another component
+		}
+
+		if visited != reachable {
+
+			return Errorf("vertex %d is reachable: %t, but visited: %t", vertex, reachable, visited)
+		}
+
+		if !reachable {
+			continue
+		}
+
+		var result, _ = run.Results[id].(algorithm.DistanceResult)
+		if result == nil || int(result.Distance()) != distance {
+
+			return Errorf("vertex %d reports level %v instead of %d", vertex, result, distance)
+		}
+
+		if Vertex(vertex) != run.Root && (!server.Graph.HasEdge(parent, Vertex(vertex)) || expected[parent] != distance-1) {
+
+			return Errorf("vertex %d at level %d has parent %d at level %d", vertex, distance, parent, expected[parent])
+		}
+	}
+	return nil
+}
+
+// VerifyEchoAggregate recomputes the aggregate from the values the nodes of
+// the root's component reported and compares it with the one of the wave.
+func VerifyEchoAggregate(server *Server, run *Run) error {
+
+	var aggregate, reported = run.Outcome.(echo.Aggregate)
+	if !reported {
+
+		return Errorf("the initiator reported no aggregate")
+	}
+
+	var expected echo.Aggregate
+	for vertex := range server.ReachableFrom(run.Root) {
+
+		var result, ok = run.Results[server.VertexIDs[vertex]].(echo.Result)
+		if !ok {
+
+			return Errorf("vertex %d reported no echo result", vertex)
+		}
+
+		var own = echo.Aggregate{Count: 1, Sum: result.Value, Min: result.Value, Max: result.Value}
+		if expected.Count == 0 {
+
+			expected = own
+
+		} else {
+
+			expected = expected.Merge(own)
+		}
+	}
+
+	if aggregate.Count != expected.Count || aggregate.Min != expected.Min || aggregate.Max != expected.Max ||
+		math.Abs(aggregate.Sum-expected.Sum) > 1e-9*math.Max(1, math.Abs(expected.Sum)) {
+
+		return Errorf("aggregate %v does not match the expected %v", aggregate, expected)
+	}
+	return nil
+}
+
+// VerifyShortestPaths compares the distances reported by the nodes with the
+// ones of Dijkstra's algorithm and checks that every node's parent lies on a
+// shortest path to it.
+func VerifyShortestPaths(server *Server, run *Run) error {
+
+	var parents, parentsError = server.TreeParents(run)
+	if parentsError != nil {
+
+		return parentsError
+	}
+
+	var expected = server.Graph.ShortestDistances(run.Root)
+	var distanceOf = func(vertex Vertex) float64 {
+
+		var result, _ = run.Results[server.VertexIDs[vertex]].(algorithm.DistanceResult)
+		if result == nil {
+
+			return math.NaN()
+		}
+		return result.Distance()
+	}
+	var equal = func(lhs float64, rhs float64) bool {
+
+		return math.Abs(lhs-rhs) <= 1e-9*math.Max(1, math.Abs(rhs))
+	}
+
+	for vertex := range server.VertexIDs {
+
+		var distance, reachable = expected[Vertex(vertex)]
+		var parent, visited = parents[Vertex(vertex)]
+		if reachable != visited {
+
+			return Errorf("vertex %d is reachable: %t, but visited: %t", vertex, reachable, visited)
+		}
+
+		if !reachable {
+			continue
+		}
+
+		if !equal(distanceOf(Vertex(vertex)), float64(distance)) {
+
+			return Errorf("vertex %d reports distance %g instead of %g", vertex, distanceOf(Vertex(vertex)), distance)
+		}
+
+		if Vertex(vertex) == run.Root {
+
+			if parent != run.Root {
+
+				return Errorf("root %d reports parent %d", run.Root, parent)
+			}
+			continue
+		}
+
+		var weight, exists = server.Graph.WeightOf(parent, Vertex(vertex))
+		if !exists || !equal(float64(expected[parent]+weight), float64(distance)) {
+
+			return Errorf("tree edge %d -> %d is not on a shortest path", parent, vertex)
+		}
+	}
+	return nil
+}
+
+// VerifyReachability checks that exactly the vertices reachable over the
+// directed links were reached, each by a link from its parent.
+func VerifyReachability(server *Server, run *Run) error {
+
+	var parents, parentsError = server.TreeParents(run)
+	if parentsError != nil {
+
+		return parentsError
+	}
+
+	var reachable = server.ReachableFrom(run.Root)
+	for vertex := range server.VertexIDs {
+
+		var parent, reached = parents[Vertex(vertex)]
+		if reached != reachable[Vertex(vertex)] {
+
+			return Errorf("vertex %d is reachable: %t, but reached: %t", vertex, reachable[Vertex(vertex)], reached)
+		}
+
+		if reached && Vertex(vertex) != run.Root && !server.Graph.HasEdge(parent, Vertex(vertex)) {
+
+			return Errorf("vertex %d was reached from %d without a link", vertex, parent)
+		}
+	}
+	Printf("[Log] [Reach]: vertex %d reaches %d of %d vertices\n", run.Root, len(reachable), len(server.VertexIDs))
+	return nil
+}
+
+// VerifySpanningTree checks that the branches reported by the nodes form a
+// spanning tree of the root's component whose weight equals the one of the
+// minimum spanning forest computed by Kruskal's algorithm. With -elect every
+// component elected its own leader, so every component has to be spanned.
+func VerifySpanningTree(server *Server, run *Run) error {
+
+	var vertices = make(map[string]Vertex)
+	for vertex, id := range server.VertexIDs {
+
+		vertices[id] = Vertex(vertex)
+	}
+
+	var reachable = server.ReachableFrom(run.Root)
+	if server.Elect {
+
+		for vertex := range server.VertexIDs {
+
+			reachable[Vertex(vertex)] = true
+		}
+	}
+
+	var components = 0
+	for _, component := range server.Graph.ConnectedComponents() {
+
+		if reachable[component[0]] {
+
+			components++
+		}
+	}
+	var tree = EdgeList{}
+
+	for vertex, id := range server.VertexIDs {
+
+		var result, reported = run.Results[id].(ghs.Result)
+		if !reported {
+
+			return Errorf("vertex %d <ID: %s> reported no GHS result", vertex, id)
+		}
+
+		for _, branchID := range result.Branches {
+
+			var neighbor, known = vertices[branchID]
+			var weight, exists = server.Graph.WeightOf(Vertex(vertex), neighbor)
+			if !known || !exists {
+
+				return Errorf("branch %d -> <ID: %s> is not part of the graph", vertex, branchID)
+			}
+
+			var other, _ = run.Results[branchID].(ghs.Result)
+			if !containsString(other.Branches, id) {
+
+				return Errorf("branch %d -> %d is only known to one endpoint", vertex, neighbor)
+			}
+
+			if !reachable[Vertex(vertex)] {
+
+				return Errorf("branch %d -> %d is outside of the root's component", vertex, neighbor)
+			}
+			if Vertex(vertex) < neighbor {
+
+				tree = append(tree, Edge{From: Vertex(vertex), To: neighbor, Weight: weight})
+			}
+		}
+	}
+
+	// branches stay inside their component, so n - c branches without a cycle
+	// span each of the c components with a tree
+	var forest = tree.Graph()
+	var acyclic = len(tree) == forest.VertexCount()-len(forest.ConnectedComponents())
+
+	if len(tree) != len(reachable)-components || !acyclic {
+
+		return Errorf("%d branches on %d vertices in %d components form no spanning tree of every component", len(tree), len(reachable), components)
+	}
+
+	var expected Weight
+	for _, edge := range server.Graph.MinimumSpanningForest() {
+
+		if reachable[edge.From] {
+
+			expected += edge.Weight
+		}
+	}
+
+	if math.Abs(float64(tree.TotalWeight()-expected)) > 1e-9*math.Max(1, float64(expected)) {
+
+		return Errorf("spanning tree weighs %g, but the minimum is %g", tree.TotalWeight(), expected)
+	}
+	Printf("[Log] [MST]: spanning tree of weight %g: %v\n", tree.TotalWeight(), tree)
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+
+	for _, element := range values {
+
+		if element == value {
+
+			return true
+		}
+	}
+	return false
+}
+
+// ReachableFrom returns the vertices the root can reach, on an undirected
+// graph this is its connected component.
+func (server *Server) ReachableFrom(root Vertex) map[Vertex]bool {
+
+	var reachable = make(map[Vertex]bool)
+	for vertex := range server.Graph.HopDistances(root) {
+
+		reachable[vertex] = true
+	}
+	return reachable
+}
+
+// TreeParents maps every visited vertex to the vertex of its parent.
+func (server *Server) TreeParents(run *Run) (map[Vertex]Vertex, error) {
+
+	var vertices = make(map[string]Vertex)
+	for vertex, id := range server.VertexIDs {
+
+		vertices[id] = Vertex(vertex)
+	}
+
+	var parents = make(map[Vertex]Vertex)
+	for vertex, id := range server.VertexIDs {
+
+		var result, reported = run.Results[id].(algorithm.TreeResult)
+		if !reported {
+
+			return nil, Errorf("vertex %d <ID: %s> reported no tree result", vertex, id)
+		}
+
+		if len(result.Parent()) == 0 {
+			continue // not visited
+		}
+
+		var parent, known = vertices[result.Parent()]
+		if !known {
+
+			return nil, Errorf("vertex %d <ID: %s> reports unknown parent <ID: %s>", vertex, id, result.Parent())
+		}
+		parents[Vertex(vertex)] = parent
+	}
+	return parents, nil
+}
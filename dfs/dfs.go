@@ -0,0 +1,193 @@
+//
+//  dfs.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+// Package dfs implements Awerbuch's distributed depth-first search. Before a
+// node passes the token on, it tells all neighbors that it is visited and
+// waits for their acknowledgements, so the token is never sent to a visited
+// node and the traversal needs O(n) time instead of O(m).
+package dfs
+
+import . "fmt"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/bfs/command"
+
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/algorithm"
+
+import "sync"
+import "encoding/gob"
+
+type Node struct {
+	guard       sync.Mutex
+	host        algorithm.Host
+	id          string
+	parentID    string
+	depth       int64
+	root        bool
+	visited     bool
+	neighbors   []string
+	unavailable map[string]bool // neighbors known to be visited or already tried
+	pendingAcks int
+	children    []string
+	messages    int
+}
+
+// Result is what a node reports to the server once the traversal is done.
+type Result struct {
+	ID       string
+	ParentID string
+	Depth    int64
+	Children []string
+	Messages int
+}
+
+func init() {
+
+	gob.Register(Result{})
+	algorithm.Register("dfs", func() algorithm.Algorithm { return new(Node) })
+}
+
+func (node *Node) Init(environment algorithm.Environment) {
+
+	node.guard.Lock()
+	node.host = environment.Host
+	node.id = environment.ID
+	node.depth = -1
+	node.neighbors = append([]string{}, environment.Neighbors...)
+	node.unavailable = make(map[string]bool)
+	node.guard.Unlock()
+}
+
+func (node *Node) HandleMessage(sender string, receiver string, command uint8, value interface{}) {
+
+	node.guard.Lock()
+	switch command {
+
+	case InitCommand:
+		node.root = true
+		node.visit(node.id, 0)
+
+	case DiscoverCommand:
+		if node.visited {
+
+			// can not happen with acknowledged visited messages, but do not adopt twice
+			node.send(sender, ReturnCommand, false)
+
+		} else {
+
+			node.visit(sender, value.(int64)+1)
+		}
+
+	case VisitedCommand:
+		node.unavailable[sender] = true
+		node.send(sender, VisitedAckCommand, nil)
+
+	case VisitedAckCommand:
+		node.pendingAcks--
+		if node.pendingAcks == 0 {
+
+			node.passToken()
+		}
+
+	case ReturnCommand:
+		if value.(bool) {
+
+			node.children = append(node.children, sender)
+		}
+		node.passToken()
+
+	default:
+		Printf("[DFS Algorithm]: Unknown command \"%d\"- do nothing\n", command)
+	}
+	node.guard.Unlock()
+}
+
+func (node *Node) Result() interface{} {
+
+	node.guard.Lock()
+	var result = Result{ID: node.id, ParentID: node.parentID, Depth: node.depth, Children: append([]string{}, node.children...), Messages: node.messages}
+	node.guard.Unlock()
+	return result
+}
+
+func (result Result) Parent() string {
+
+	return result.ParentID
+}
+
+func (result Result) MessageCount() int {
+
+	return result.Messages
+}
+
+// String renders the tree edges of the node as GraphPlot code.
+func (result Result) String() string {
+
+	var description = Sprintf("<ID: %s Parent: %s Depth: %d>", result.ID, result.ParentID, result.Depth)
+
+	if len(result.Children) > 0 {
+
+		description += "\n[Log] [Tree] [Code-Part]:"
+		for _, childID := range result.Children {
+
+			description += Sprintf("\n%s -> %s, ", result.ID, childID)
+		}
+	}
+	return description
+}
+
+// The following helpers expect the guard to be locked by the caller.
+func (node *Node) visit(parentID string, depth int64) {
+
+	node.visited = true
+	node.parentID = parentID
+	node.depth = depth
+	node.unavailable[parentID] = true
+
+	for _, neighborID := range node.neighbors {
+
+		if neighborID != parentID {
+
+			node.pendingAcks++
+			node.send(neighborID, VisitedCommand, nil)
+		}
+	}
+
+	if node.pendingAcks == 0 {
+
+		node.passToken()
+	}
+}
+
+// passToken sends the token to the next neighbor that is not visited yet or
+// returns it to the parent.
+func (node *Node) passToken() {
+
+	for _, neighborID := range node.neighbors {
+
+		if !node.unavailable[neighborID] {
+
+			node.unavailable[neighborID] = true
+			node.send(neighborID, DiscoverCommand, node.depth)
+			return
+		}
+	}
+
+	if node.root {
+
+		node.send("server", CompleteCommand, nil)
+
+	} else {
+
+		node.send(node.parentID, ReturnCommand, true)
+	}
+}
+
+func (node *Node) send(receiver string, command uint8, value interface{}) {
+
+	node.messages++
+	node.host.SendMessage(node.id, receiver, command, value)
+}
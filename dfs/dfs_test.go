@@ -0,0 +1,158 @@
+//
+//  dfs_test.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+package dfs
+
+import . "fmt"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/bfs/command"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/graph"
+
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/algorithm"
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/algorithm/algorithmtest"
+
+import "testing"
+
+var idOf = algorithmtest.IDOf
+var graphOf = algorithmtest.UnweightedGraphOf
+
+// search starts the traversal at the root and delivers every message.
+func search(graph *Graph, root Vertex, seed int64) ([]algorithmtest.Sent, map[string]Result) {
+
+	var network = algorithmtest.NetworkWith(seed)
+	var nodes = algorithmtest.NodesOf(graph, network, func() algorithm.Algorithm { return new(Node) })
+
+	network.SendMessage("server", idOf(root), InitCommand, nil)
+	network.Deliver()
+
+	var results = make(map[string]Result)
+	for vertex, node := range nodes {
+
+		results[idOf(vertex)] = node.Result().(Result)
+	}
+	return network.ServerMessages(), results
+}
+
+// isAncestor follows the parents of the descendant up to the root.
+func isAncestor(ancestor string, descendant string, results map[string]Result) bool {
+
+	for id := descendant; ; id = results[id].ParentID {
+
+		if id == ancestor {
+
+			return true
+		}
+
+		if results[id].ParentID == id {
+
+			return false
+		}
+	}
+}
+
+func TestNodeBuildsADepthFirstTree(t *testing.T) {
+
+	var tests = []struct {
+		name  string
+		graph *Graph
+	}{
+		{"single edge", graphOf([][2]int{{0, 1}})},
+		{"path", graphOf([][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 4}})},
+		{"cycle", graphOf([][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 5}, {5, 0}})},
+		{"star", graphOf([][2]int{{0, 1}, {0, 2}, {0, 3}, {0, 4}})},
+		{"complete", graphOf([][2]int{{0, 1}, {0, 2}, {0, 3}, {0, 4}, {1, 2}, {1, 3}, {1, 4}, {2, 3}, {2, 4}, {3, 4}})},
+		{"random", algorithmtest.RandomGraph(12, 10, 1, 1)},
+		{"random dense", algorithmtest.RandomGraph(10, 25, 1, 2)},
+	}
+
+	for _, test := range tests {
+
+		for seed := int64(0); seed < 10; seed++ {
+
+			var name = Sprintf("%s, seed %d", test.name, seed)
+			var root = test.graph.Vertices()[seed%int64(test.graph.VertexCount())]
+			var server, results = search(test.graph, root, seed)
+
+			if len(server) != 1 || server[0].Sender != idOf(root) || server[0].Command != CompleteCommand {
+
+				t.Errorf("%s: the server received %v, expected a single completion of the root", name, server)
+
+			} else if server[0].InFlight != 0 {
+
+				t.Errorf("%s: the root completed with %d messages in flight", name, server[0].InFlight)
+			}
+
+			// visited and its ack over every edge but the tree edge to the
+			// parent, discover and return over the tree edges, no rejected
+			// discover, and the completion
+			var messages = 0
+			for _, result := range results {
+
+				messages += result.Messages
+			}
+
+			if expected := 4*test.graph.EdgeCount() + 1; messages != expected {
+
+				t.Errorf("%s: the nodes sent %d messages, expected %d", name, messages, expected)
+			}
+
+			for _, vertex := range test.graph.Vertices() {
+
+				var id = idOf(vertex)
+				var result = results[id]
+				if vertex == root {
+
+					if result.ParentID != id || result.Depth != 0 {
+
+						t.Errorf("%s: root has parent %s at depth %d", name, result.ParentID, result.Depth)
+					}
+					continue
+				}
+
+				var parent, found = results[result.ParentID]
+				if !found || parent.Depth != result.Depth-1 {
+
+					t.Errorf("%s: vertex %d at depth %d has parent %q at depth %d", name, vertex, result.Depth, result.ParentID, parent.Depth)
+					continue
+				}
+
+				var listed = false
+				for _, childID := range parent.Children {
+
+					listed = listed || childID == id
+				}
+
+				if !listed {
+
+					t.Errorf("%s: parent %s does not list vertex %d among its children %v", name, result.ParentID, vertex, parent.Children)
+				}
+			}
+
+			// a depth-first tree has no cross edges
+			for _, edge := range test.graph.EdgeList() {
+
+				var from, to = idOf(edge.From), idOf(edge.To)
+				if !isAncestor(from, to, results) && !isAncestor(to, from, results) {
+
+					t.Errorf("%s: edge %v connects two branches of the tree", name, edge)
+				}
+			}
+		}
+	}
+}
+
+func TestIsolatedRootCompletesAlone(t *testing.T) {
+
+	var graph = NewGraph()
+	graph.AddVertex(0)
+
+	var server, results = search(graph, 0, 0)
+	if len(server) != 1 || server[0].Command != CompleteCommand || results[idOf(0)].Depth != 0 {
+
+		t.Errorf("isolated root has result %v and the server received %v, expected a single completion", results[idOf(0)], server)
+	}
+}
@@ -0,0 +1,199 @@
+//
+//  echo.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+// Package echo implements the echo (wave) algorithm to aggregate a per-node
+// value over the overlay. The initiator explores all neighbors, every node
+// answers its parent with the aggregate of its subtree once it heard from all
+// neighbors, and the initiator reports the global aggregate to the server.
+package echo
+
+import . "fmt"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/bfs/command"
+
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/algorithm"
+
+import "sync"
+import "math"
+import "encoding/gob"
+
+type Node struct {
+	guard     sync.Mutex
+	host      algorithm.Host
+	id        string
+	parentID  string
+	root      bool
+	explored  bool
+	neighbors []string
+	waiting   int // neighbors that did not answer yet
+	value     float64
+	aggregate Aggregate
+	messages  int
+}
+
+// Aggregate is the value of EchoCommand and of the initiator's CompleteCommand.
+type Aggregate struct {
+	Count int
+	Sum   float64
+	Min   float64
+	Max   float64
+}
+
+// Result is what a node reports to the server once the wave is done.
+type Result struct {
+	ID       string
+	ParentID string
+	Value    float64
+	Messages int
+}
+
+var Aggregates = []string{"count", "sum", "min", "max", "mean"}
+
+func init() {
+
+	gob.Register(Aggregate{})
+	gob.Register(Result{})
+	algorithm.Register("echo", func() algorithm.Algorithm { return new(Node) })
+}
+
+func (node *Node) Init(environment algorithm.Environment) {
+
+	node.guard.Lock()
+	node.host = environment.Host
+	node.id = environment.ID
+	node.neighbors = append([]string{}, environment.Neighbors...)
+	node.value = environment.Value
+	node.aggregate = Aggregate{Count: 1, Sum: node.value, Min: node.value, Max: node.value}
+	node.guard.Unlock()
+}
+
+func (node *Node) HandleMessage(sender string, receiver string, command uint8, value interface{}) {
+
+	node.guard.Lock()
+	switch command {
+
+	case InitCommand:
+		node.root = true
+		node.explore(node.id)
+
+	case ExploreCommand:
+		if node.explored {
+
+			// the explore crossed ours on a non-tree edge, which counts as answer
+			node.waiting--
+			node.checkEcho()
+
+		} else {
+
+			node.explore(sender)
+		}
+
+	case EchoCommand:
+		node.aggregate = node.aggregate.Merge(value.(Aggregate))
+		node.waiting--
+		node.checkEcho()
+
+	default:
+		Printf("[Echo Algorithm]: Unknown command \"%d\"- do nothing\n", command)
+	}
+	node.guard.Unlock()
+}
+
+func (node *Node) Result() interface{} {
+
+	node.guard.Lock()
+	var result = Result{ID: node.id, ParentID: node.parentID, Value: node.value, Messages: node.messages}
+	node.guard.Unlock()
+	return result
+}
+
+func (aggregate Aggregate) Merge(other Aggregate) Aggregate {
+
+	return Aggregate{
+		Count: aggregate.Count + other.Count,
+		Sum:   aggregate.Sum + other.Sum,
+		Min:   math.Min(aggregate.Min, other.Min),
+		Max:   math.Max(aggregate.Max, other.Max),
+	}
+}
+
+// Value returns one of the Aggregates by name.
+func (aggregate Aggregate) Value(name string) (float64, bool) {
+
+	switch name {
+	case "count":
+		return float64(aggregate.Count), true
+	case "sum":
+		return aggregate.Sum, true
+	case "min":
+		return aggregate.Min, true
+	case "max":
+		return aggregate.Max, true
+	case "mean":
+		return aggregate.Sum / float64(aggregate.Count), true
+	}
+	return 0, false
+}
+
+func (aggregate Aggregate) String() string {
+
+	return Sprintf("<Count: %d Sum: %g Min: %g Max: %g>", aggregate.Count, aggregate.Sum, aggregate.Min, aggregate.Max)
+}
+
+func (result Result) Parent() string {
+
+	return result.ParentID
+}
+
+func (result Result) MessageCount() int {
+
+	return result.Messages
+}
+
+func (result Result) String() string {
+
+	return Sprintf("<ID: %s Parent: %s Value: %g>", result.ID, result.ParentID, result.Value)
+}
+
+// The following helpers expect the guard to be locked by the caller.
+func (node *Node) explore(parentID string) {
+
+	node.explored = true
+	node.parentID = parentID
+
+	for _, neighborID := range node.neighbors {
+
+		if neighborID != parentID {
+
+			node.waiting++
+			node.send(neighborID, ExploreCommand, nil)
+		}
+	}
+	node.checkEcho()
+}
+
+func (node *Node) checkEcho() {
+
+	if node.waiting > 0 {
+		return
+	}
+
+	if node.root {
+
+		node.send("server", CompleteCommand, node.aggregate)
+
+	} else {
+
+		node.send(node.parentID, EchoCommand, node.aggregate)
+	}
+}
+
+func (node *Node) send(receiver string, command uint8, value interface{}) {
+
+	node.messages++
+	node.host.SendMessage(node.id, receiver, command, value)
+}
@@ -0,0 +1,171 @@
+//
+//  echo_test.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+package echo
+
+import . "fmt"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/bfs/command"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/graph"
+
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/algorithm/algorithmtest"
+
+import "testing"
+
+var idOf = algorithmtest.IDOf
+var graphOf = algorithmtest.UnweightedGraphOf
+
+// valueOf is the per-node value of a vertex, negative for some vertices.
+func valueOf(vertex Vertex) float64 {
+
+	return float64(int(vertex)*7%11) - 3.5
+}
+
+// wave starts the echo at the root and delivers every message.
+func wave(graph *Graph, root Vertex, seed int64) ([]algorithmtest.Sent, map[string]Result) {
+
+	var network = algorithmtest.NetworkWith(seed)
+	var nodes = make(map[Vertex]*Node)
+	for _, vertex := range graph.Vertices() {
+
+		var environment = algorithmtest.EnvironmentOf(graph, vertex, network)
+		environment.Value = valueOf(vertex)
+		nodes[vertex] = new(Node)
+		nodes[vertex].Init(environment)
+		network.Attach(idOf(vertex), nodes[vertex])
+	}
+
+	network.SendMessage("server", idOf(root), InitCommand, nil)
+	network.Deliver()
+
+	var results = make(map[string]Result)
+	for vertex, node := range nodes {
+
+		results[idOf(vertex)] = node.Result().(Result)
+	}
+	return network.ServerMessages(), results
+}
+
+func TestWaveAggregatesEveryValueOnce(t *testing.T) {
+
+	var tests = []struct {
+		name  string
+		graph *Graph
+	}{
+		{"single edge", graphOf([][2]int{{0, 1}})},
+		{"triangle", graphOf([][2]int{{0, 1}, {1, 2}, {2, 0}})},
+		{"cycle", graphOf([][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 5}, {5, 0}})},
+		{"star", graphOf([][2]int{{0, 1}, {0, 2}, {0, 3}, {0, 4}})},
+		{"complete", graphOf([][2]int{{0, 1}, {0, 2}, {0, 3}, {1, 2}, {1, 3}, {2, 3}})},
+		{"random", algorithmtest.RandomGraph(12, 10, 1, 1)},
+	}
+
+	for _, test := range tests {
+
+		var expected = Aggregate{Count: test.graph.VertexCount(), Min: valueOf(0), Max: valueOf(0)}
+		for _, vertex := range test.graph.Vertices() {
+
+			var value = valueOf(vertex)
+			expected.Sum += value
+			if value < expected.Min {
+
+				expected.Min = value
+			}
+			if value > expected.Max {
+
+				expected.Max = value
+			}
+		}
+
+		for seed := int64(0); seed < 10; seed++ {
+
+			var name = Sprintf("%s, seed %d", test.name, seed)
+			var root = test.graph.Vertices()[seed%int64(test.graph.VertexCount())]
+			var server, results = wave(test.graph, root, seed)
+
+			if len(server) != 1 || server[0].Sender != idOf(root) || server[0].Command != CompleteCommand {
+
+				t.Errorf("%s: the server received %v, expected a single completion of the root", name, server)
+				continue
+			}
+
+			if aggregate := server[0].Value.(Aggregate); aggregate != expected {
+
+				t.Errorf("%s: the root reported %v, expected %v", name, aggregate, expected)
+			}
+
+			if server[0].InFlight != 0 {
+
+				t.Errorf("%s: the root completed with %d messages in flight", name, server[0].InFlight)
+			}
+
+			// explore over every edge but the tree edge to the parent, an echo
+			// over every tree edge and the completion
+			var messages = 0
+			for _, result := range results {
+
+				messages += result.Messages
+			}
+
+			if expected := 2*test.graph.EdgeCount() + 1; messages != expected {
+
+				t.Errorf("%s: the nodes sent %d messages, expected %d", name, messages, expected)
+			}
+
+			var vertices = make(map[string]Vertex)
+			for _, vertex := range test.graph.Vertices() {
+
+				vertices[idOf(vertex)] = vertex
+			}
+
+			for _, vertex := range test.graph.Vertices() {
+
+				var result = results[idOf(vertex)]
+				if vertex == root {
+
+					if result.ParentID != idOf(root) {
+
+						t.Errorf("%s: root has parent %s", name, result.ParentID)
+					}
+
+				} else if parent, found := vertices[result.ParentID]; !found || !test.graph.HasEdge(vertex, parent) {
+
+					t.Errorf("%s: vertex %d has parent %q, which is no neighbor", name, vertex, result.ParentID)
+				}
+			}
+		}
+	}
+}
+
+func TestAggregateValue(t *testing.T) {
+
+	var aggregate = Aggregate{Count: 1, Sum: 2, Min: 2, Max: 2}.Merge(Aggregate{Count: 3, Sum: -6, Min: -4, Max: 1})
+
+	var tests = []struct {
+		name     string
+		expected float64
+	}{
+		{"count", 4},
+		{"sum", -4},
+		{"min", -4},
+		{"max", 2},
+		{"mean", -1},
+	}
+
+	for _, test := range tests {
+
+		if value, ok := aggregate.Value(test.name); !ok || value != test.expected {
+
+			t.Errorf("%s of %v is %v (%v), expected %v", test.name, aggregate, value, ok, test.expected)
+		}
+	}
+
+	if _, ok := aggregate.Value("median"); ok {
+
+		t.Errorf("median of %v is known, expected only %v", aggregate, Aggregates)
+	}
+}
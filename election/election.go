@@ -0,0 +1,145 @@
+//
+//  election.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+// Package election elects the node with the largest ID as leader using echo
+// waves with extinction: every node starts a wave tagged with its own ID, a
+// node only takes part in the wave with the largest ID it has seen so far and
+// drops all others. The only wave that completes is the one of the largest
+// ID, so its initiator knows it won.
+package election
+
+import . "fmt"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/bfs/command"
+
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/algorithm"
+
+import "sync"
+
+type Node struct {
+	guard     sync.Mutex
+	host      algorithm.Host
+	id        string
+	neighbors []string
+	wave      string // largest wave seen so far, the current leader candidate
+	parentID  string
+	waiting   int
+	elected   func()
+}
+
+// IsElectionCommand reports whether the command belongs to the election and
+// has to be delivered to the election node instead of the algorithm.
+func IsElectionCommand(command uint8) bool {
+
+	return command == ElectCommand || command == WaveCommand || command == WaveEchoCommand
+}
+
+// NodeWith creates an election node, elected is called on the winner only.
+func NodeWith(environment algorithm.Environment, elected func()) *Node {
+
+	var node = new(Node)
+	node.host = environment.Host
+	node.id = environment.ID
+	node.neighbors = append([]string{}, environment.Neighbors...)
+	node.elected = elected
+	return node
+}
+
+func (node *Node) HandleMessage(sender string, receiver string, command uint8, value interface{}) {
+
+	node.guard.Lock()
+	switch command {
+
+	case ElectCommand:
+		// a wave that arrived first started the candidacy of the node already
+		if len(node.wave) == 0 {
+
+			node.join(node.id, "")
+		}
+
+	case WaveCommand:
+		var wave = value.(string)
+		if len(node.wave) == 0 {
+
+			// take part as a candidate before comparing, otherwise a smaller
+			// wave could complete before the ElectCommand of the node arrives
+			node.join(node.id, "")
+		}
+
+		if wave > node.wave {
+
+			node.join(wave, sender)
+
+		} else if wave == node.wave {
+
+			// the wave crossed ours on a non-tree edge
+			node.waiting--
+			node.checkEcho()
+		}
+
+	case WaveEchoCommand:
+		if value.(string) == node.wave {
+
+			node.waiting--
+			node.checkEcho()
+		}
+
+	default:
+		Printf("[Election]: Unknown command \"%d\"- do nothing\n", command)
+	}
+	node.guard.Unlock()
+}
+
+// Leader returns the largest ID seen so far, which is the leader once the
+// election is over.
+func (node *Node) Leader() string {
+
+	node.guard.Lock()
+	var leader = node.wave
+	node.guard.Unlock()
+	return leader
+}
+
+// The following helpers expect the guard to be locked by the caller.
+func (node *Node) join(wave string, parentID string) {
+
+	node.wave = wave
+	node.parentID = parentID
+	node.waiting = 0
+
+	for _, neighborID := range node.neighbors {
+
+		if neighborID != parentID {
+
+			node.waiting++
+			node.send(neighborID, WaveCommand, wave)
+		}
+	}
+	node.checkEcho()
+}
+
+func (node *Node) checkEcho() {
+
+	if node.waiting > 0 {
+		return
+	}
+
+	if len(node.parentID) > 0 {
+
+		node.send(node.parentID, WaveEchoCommand, node.wave)
+
+	} else {
+
+		node.send("server", LeaderCommand, node.id)
+		go node.elected() // the callback may deliver messages to this node again
+	}
+}
+
+func (node *Node) send(receiver string, command uint8, value interface{}) {
+
+	node.host.SendMessage(node.id, receiver, command, value)
+}
@@ -0,0 +1,127 @@
+//
+//  election_test.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+package election
+
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/bfs/command"
+
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/algorithm"
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/algorithm/algorithmtest"
+
+import "testing"
+import "time"
+
+// elect runs an election on the undirected graph of edges. The ElectCommands
+// in late are delivered only after every other message was delivered.
+func elect(edges [][2]string, late []string, seed int64) ([]string, map[string]*Node, []string) {
+
+	var network = algorithmtest.NetworkWith(seed)
+	var neighbors = make(map[string][]string)
+	for _, edge := range edges {
+
+		neighbors[edge[0]] = append(neighbors[edge[0]], edge[1])
+		neighbors[edge[1]] = append(neighbors[edge[1]], edge[0])
+	}
+
+	var elected = make(chan string, len(neighbors))
+	var nodes = make(map[string]*Node)
+	for id, ids := range neighbors {
+
+		var id = id
+		nodes[id] = NodeWith(algorithm.Environment{Host: network, ID: id, Neighbors: ids}, func() { elected <- id })
+		network.Attach(id, nodes[id])
+	}
+
+	var isLate = make(map[string]bool)
+	for _, id := range late {
+
+		isLate[id] = true
+	}
+
+	for id := range nodes {
+
+		if !isLate[id] {
+
+			network.SendMessage("server", id, ElectCommand, nil)
+		}
+	}
+	network.Deliver()
+
+	for _, id := range late {
+
+		network.SendMessage("server", id, ElectCommand, nil)
+		network.Deliver()
+	}
+
+	var leaders = []string{}
+	for _, message := range network.ServerMessages() {
+
+		leaders = append(leaders, message.Value.(string))
+	}
+
+	// elected is called on its own routine, right after the LeaderCommand
+	var winners = []string{}
+	for range leaders {
+
+		select {
+		case id := <-elected:
+			winners = append(winners, id)
+		case <-time.After(time.Second):
+		}
+	}
+	return leaders, nodes, winners
+}
+
+func TestElectionChoosesTheLargestID(t *testing.T) {
+
+	var path = [][2]string{{"a", "b"}, {"b", "c"}, {"c", "d"}}
+	var cycle = [][2]string{{"a", "b"}, {"b", "c"}, {"c", "d"}, {"d", "e"}, {"e", "a"}}
+	var star = [][2]string{{"c", "a"}, {"c", "b"}, {"c", "d"}, {"c", "e"}}
+	var complete = [][2]string{{"a", "b"}, {"a", "c"}, {"a", "d"}, {"b", "c"}, {"b", "d"}, {"c", "d"}}
+
+	var tests = []struct {
+		name   string
+		edges  [][2]string
+		late   []string
+		leader string
+	}{
+		{"path", path, nil, "d"},
+		{"path, largest ID elects last", path, []string{"d"}, "d"},
+		{"path, only the smallest ID elects", path, []string{"b", "c", "d"}, "d"},
+		{"cycle, largest ID elects last", cycle, []string{"e"}, "e"},
+		{"star, largest ID is a leaf and elects last", star, []string{"e"}, "e"},
+		{"complete, largest ID elects last", complete, []string{"d"}, "d"},
+		{"single edge, largest ID elects last", [][2]string{{"a", "b"}}, []string{"b"}, "b"},
+	}
+
+	for _, test := range tests {
+
+		for seed := int64(0); seed < 20; seed++ {
+
+			var leaders, nodes, winners = elect(test.edges, test.late, seed)
+
+			if len(leaders) != 1 || leaders[0] != test.leader {
+
+				t.Errorf("%s, seed %d: the server was told about leaders %v, expected [%s]", test.name, seed, leaders, test.leader)
+			}
+
+			if len(winners) != 1 || winners[0] != test.leader {
+
+				t.Errorf("%s, seed %d: elected was called on %v, expected [%s]", test.name, seed, winners, test.leader)
+			}
+
+			for id, node := range nodes {
+
+				if leader := node.Leader(); leader != test.leader {
+
+					t.Errorf("%s, seed %d: node %s knows leader %s, expected %s", test.name, seed, id, leader, test.leader)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,435 @@
+//
+//  ghs.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+// Package ghs computes the minimum spanning tree of the root's component with
+// the algorithm of Gallager, Humblet and Spira.
+package ghs
+
+import . "fmt"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/bfs/command"
+
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/algorithm"
+
+import "math"
+import "sort"
+import "sync"
+import "encoding/gob"
+
+type nodeState uint8
+
+const (
+	sleeping nodeState = iota
+	find
+	found
+)
+
+type edgeState uint8
+
+const (
+	basic edgeState = iota
+	branch
+	rejected
+)
+
+// EdgeWeight makes the weights of all links distinct, as GHS requires, by
+// breaking ties with the IDs of both endpoints. It also names the fragment
+// whose core is the link.
+type EdgeWeight struct {
+	Weight float64
+	Low    string
+	High   string
+}
+
+var infinity = EdgeWeight{Weight: math.Inf(1)}
+
+// Values of the GHS commands, Accept, Reject and ChangeRoot carry none.
+type Connect struct {
+	Level int
+}
+
+type Initiate struct {
+	Level    int
+	Fragment EdgeWeight
+	Find     bool // the state of the fragment is Find, otherwise Found
+}
+
+type Test struct {
+	Level    int
+	Fragment EdgeWeight
+}
+
+type Report struct {
+	Weight EdgeWeight
+}
+
+// Node follows the 1983 paper closely. Messages that cannot be answered yet
+// (Connect and Test from a higher level, Report to a core that is still
+// searching) are deferred and retried after every state change. GHS needs
+// FIFO links, which the runtime provides by delivering sequentially.
+type Node struct {
+	guard      sync.Mutex
+	host       algorithm.Host
+	id         string
+	neighbors  []string // sorted by weight
+	weights    map[string]EdgeWeight
+	edges      map[string]edgeState
+	state      nodeState
+	level      int
+	fragment   EdgeWeight
+	findCount  int
+	inBranch   string
+	bestEdge   string
+	bestWeight EdgeWeight
+	testEdge   string
+	deferred   []deferredMessage
+	halted     bool
+	messages   int
+}
+
+type deferredMessage struct {
+	sender  string
+	command uint8
+	value   interface{}
+}
+
+type Result struct {
+	ID       string
+	Branches []string // neighbors linked by a tree edge
+	Level    int
+	Halted   bool // the node is an endpoint of the final core
+	Messages int
+}
+
+func init() {
+
+	gob.Register(Connect{})
+	gob.Register(Initiate{})
+	gob.Register(Test{})
+	gob.Register(Report{})
+	gob.Register(Result{})
+	algorithm.Register("ghs", func() algorithm.Algorithm { return new(Node) })
+}
+
+func (node *Node) Init(environment algorithm.Environment) {
+
+	node.guard.Lock()
+	node.host = environment.Host
+	node.id = environment.ID
+	node.neighbors = append([]string{}, environment.Neighbors...)
+	node.weights = make(map[string]EdgeWeight)
+	node.edges = make(map[string]edgeState)
+
+	for _, neighborID := range node.neighbors {
+
+		var weight = EdgeWeight{Weight: environment.Weights[neighborID], Low: node.id, High: neighborID}
+		if weight.High < weight.Low {
+
+			weight.Low, weight.High = weight.High, weight.Low
+		}
+		node.weights[neighborID] = weight
+	}
+	sort.Slice(node.neighbors, func(i int, j int) bool {
+
+		return node.weights[node.neighbors[i]].Less(node.weights[node.neighbors[j]])
+	})
+	node.bestWeight = infinity
+	node.guard.Unlock()
+}
+
+func (node *Node) HandleMessage(sender string, receiver string, command uint8, value interface{}) {
+
+	node.guard.Lock()
+	if !node.handle(sender, command, value) {
+
+		node.deferred = append(node.deferred, deferredMessage{sender: sender, command: command, value: value})
+
+	} else {
+
+		// every handled message may change the state a deferred one waits for
+		for progress := true; progress; {
+
+			progress = false
+			for index, message := range node.deferred {
+
+				if node.handle(message.sender, message.command, message.value) {
+
+					node.deferred = append(node.deferred[:index], node.deferred[index+1:]...)
+					progress = true
+					break
+				}
+			}
+		}
+	}
+	node.guard.Unlock()
+}
+
+func (node *Node) Result() interface{} {
+
+	node.guard.Lock()
+	var branches []string
+	for _, neighborID := range node.neighbors {
+
+		if node.edges[neighborID] == branch {
+
+			branches = append(branches, neighborID)
+		}
+	}
+	var result = Result{ID: node.id, Branches: branches, Level: node.level, Halted: node.halted, Messages: node.messages}
+	node.guard.Unlock()
+	return result
+}
+
+// The following helpers expect the guard to be locked by the caller.
+
+// handle returns false if the message has to be deferred.
+func (node *Node) handle(sender string, command uint8, value interface{}) bool {
+
+	switch command {
+
+	case InitCommand:
+		if node.state == sleeping {
+
+			node.wakeup()
+		}
+
+	case ConnectCommand:
+		var connect = value.(Connect)
+		if node.state == sleeping {
+
+			node.wakeup()
+		}
+
+		if connect.Level < node.level {
+
+			// absorb the lower fragment, it joins the current search if there is one
+			node.edges[sender] = branch
+			node.send(sender, InitiateCommand, Initiate{Level: node.level, Fragment: node.fragment, Find: node.state == find})
+			if node.state == find {
+
+				node.findCount++
+			}
+
+		} else if node.edges[sender] == basic {
+
+			return false
+
+		} else {
+
+			// both fragments chose the same link, it becomes the new core
+			node.send(sender, InitiateCommand, Initiate{Level: node.level + 1, Fragment: node.weights[sender], Find: true})
+		}
+
+	case InitiateCommand:
+		var initiate = value.(Initiate)
+		node.level = initiate.Level
+		node.fragment = initiate.Fragment
+		node.state = found
+		if initiate.Find {
+
+			node.state = find
+		}
+		node.inBranch = sender
+		node.bestEdge = ""
+		node.bestWeight = infinity
+
+		for _, neighborID := range node.neighbors {
+
+			if neighborID != sender && node.edges[neighborID] == branch {
+
+				node.send(neighborID, InitiateCommand, initiate)
+				if initiate.Find {
+
+					node.findCount++
+				}
+			}
+		}
+
+		if initiate.Find {
+
+			node.test()
+		}
+
+	case TestCommand:
+		var test = value.(Test)
+		if node.state == sleeping {
+
+			node.wakeup()
+		}
+
+		if test.Level > node.level {
+
+			return false
+
+		} else if test.Fragment != node.fragment {
+
+			node.send(sender, AcceptCommand, nil)
+
+		} else {
+
+			if node.edges[sender] == basic {
+
+				node.edges[sender] = rejected
+			}
+
+			if node.testEdge != sender {
+
+				node.send(sender, RejectCommand, nil)
+
+			} else {
+
+				node.test()
+			}
+		}
+
+	case AcceptCommand:
+		node.testEdge = ""
+		if node.weights[sender].Less(node.bestWeight) {
+
+			node.bestEdge = sender
+			node.bestWeight = node.weights[sender]
+		}
+		node.report()
+
+	case RejectCommand:
+		if node.edges[sender] == basic {
+
+			node.edges[sender] = rejected
+		}
+		node.test()
+
+	case ReportCommand:
+		var report = value.(Report)
+		if sender != node.inBranch {
+
+			node.findCount--
+			if report.Weight.Less(node.bestWeight) {
+
+				node.bestWeight = report.Weight
+				node.bestEdge = sender
+			}
+			node.report()
+
+		} else if node.state == find {
+
+			return false
+
+		} else if node.bestWeight.Less(report.Weight) {
+
+			node.changeRoot()
+
+		} else if report.Weight == infinity && node.bestWeight == infinity {
+
+			node.halt()
+		}
+
+	case ChangeRootCommand:
+		node.changeRoot()
+
+	default:
+		Printf("[GHS Algorithm]: Unknown command \"%d\"- do nothing\n", command)
+	}
+	return true
+}
+
+func (node *Node) wakeup() {
+
+	node.level = 0
+	node.state = found
+	node.findCount = 0
+
+	if len(node.neighbors) == 0 {
+
+		// an isolated node is a spanning tree on its own
+		node.halt()
+		return
+	}
+
+	var minimum = node.neighbors[0]
+	node.edges[minimum] = branch
+	node.send(minimum, ConnectCommand, Connect{Level: 0})
+}
+
+func (node *Node) test() {
+
+	for _, neighborID := range node.neighbors {
+
+		if node.edges[neighborID] == basic {
+
+			node.testEdge = neighborID
+			node.send(neighborID, TestCommand, Test{Level: node.level, Fragment: node.fragment})
+			return
+		}
+	}
+	node.testEdge = ""
+	node.report()
+}
+
+func (node *Node) report() {
+
+	if node.findCount == 0 && len(node.testEdge) == 0 {
+
+		node.state = found
+		node.send(node.inBranch, ReportCommand, Report{Weight: node.bestWeight})
+	}
+}
+
+func (node *Node) changeRoot() {
+
+	if node.edges[node.bestEdge] == branch {
+
+		node.send(node.bestEdge, ChangeRootCommand, nil)
+
+	} else {
+
+		node.send(node.bestEdge, ConnectCommand, Connect{Level: node.level})
+		node.edges[node.bestEdge] = branch
+	}
+}
+
+func (node *Node) halt() {
+
+	if node.halted {
+		return
+	}
+	node.halted = true
+
+	// both endpoints of the core halt, the one with the larger ID reports it
+	if len(node.inBranch) == 0 || node.id > node.inBranch {
+
+		node.send("server", CompleteCommand, nil)
+	}
+}
+
+func (node *Node) send(receiver string, command uint8, value interface{}) {
+
+	node.messages++
+	node.host.SendMessage(node.id, receiver, command, value)
+}
+
+func (weight EdgeWeight) Less(other EdgeWeight) bool {
+
+	if weight.Weight != other.Weight {
+
+		return weight.Weight < other.Weight
+	}
+	if weight.Low != other.Low {
+
+		return weight.Low < other.Low
+	}
+	return weight.High < other.High
+}
+
+func (result Result) MessageCount() int {
+
+	return result.Messages
+}
+
+func (result Result) String() string {
+
+	return Sprintf("<ID: %s Level: %d Branches: %v>", result.ID, result.Level, result.Branches)
+}
@@ -0,0 +1,227 @@
+//
+//  ghs_test.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+package ghs
+
+import . "fmt"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/bfs/command"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/graph"
+
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/algorithm"
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/algorithm/algorithmtest"
+
+import "math/rand"
+import "sort"
+import "testing"
+
+var idOf = algorithmtest.IDOf
+var graphOf = algorithmtest.GraphOf
+
+// runGHS wakes up the given vertices and delivers messages until none is
+// left. It returns the senders of the CompleteCommands and the results.
+func runGHS(graph *Graph, awake []Vertex, seed int64) ([]string, map[Vertex]Result) {
+
+	var network = algorithmtest.NetworkWith(seed)
+	var nodes = algorithmtest.NodesOf(graph, network, func() algorithm.Algorithm { return new(Node) })
+
+	for _, vertex := range awake {
+
+		network.SendMessage("server", idOf(vertex), InitCommand, nil)
+	}
+	network.Deliver()
+
+	var completed = []string{}
+	for _, message := range network.ServerMessages() {
+
+		completed = append(completed, message.Sender)
+	}
+
+	var results = make(map[Vertex]Result)
+	for vertex, node := range nodes {
+
+		results[vertex] = node.Result().(Result)
+	}
+	return completed, results
+}
+
+// branchesOf returns the tree edges both endpoints agree on, with the smaller
+// vertex first, and an error for a branch only one endpoint knows.
+func branchesOf(graph *Graph, results map[Vertex]Result) (EdgeList, error) {
+
+	var vertices = make(map[string]Vertex)
+	for _, vertex := range graph.Vertices() {
+
+		vertices[idOf(vertex)] = vertex
+	}
+
+	var tree = EdgeList{}
+	for vertex, result := range results {
+
+		for _, branchID := range result.Branches {
+
+			var neighbor = vertices[branchID]
+			var agreed = false
+			for _, id := range results[neighbor].Branches {
+
+				agreed = agreed || id == idOf(vertex)
+			}
+
+			if !agreed {
+
+				return nil, Errorf("branch %d -> %d is only known to one endpoint", vertex, neighbor)
+			}
+
+			if vertex < neighbor {
+
+				var weight, _ = graph.WeightOf(vertex, neighbor)
+				tree = append(tree, Edge{From: vertex, To: neighbor, Weight: weight})
+			}
+		}
+	}
+	return tree, nil
+}
+
+func sortedEdges(edges EdgeList) EdgeList {
+
+	var sorted = EdgeList{}
+	for _, edge := range edges {
+
+		if edge.From > edge.To {
+
+			edge.From, edge.To = edge.To, edge.From
+		}
+		sorted = append(sorted, edge)
+	}
+	sort.Slice(sorted, func(i int, j int) bool {
+
+		return sorted[i].From < sorted[j].From || sorted[i].From == sorted[j].From && sorted[i].To < sorted[j].To
+	})
+	return sorted
+}
+
+// randomGraph creates a reproducible graph with weights in [1, maxWeight].
+func randomGraph(vertices int, edgeProbability float64, maxWeight int, seed int64) *Graph {
+
+	var random = rand.New(rand.NewSource(seed))
+	var graph = NewGraph()
+	for from := 0; from < vertices; from++ {
+
+		graph.AddVertex(Vertex(from))
+		for to := from + 1; to < vertices; to++ {
+
+			if random.Float64() < edgeProbability {
+
+				graph.AddEdge(Edge{From: Vertex(from), To: Vertex(to), Weight: Weight(random.Intn(maxWeight) + 1)})
+			}
+		}
+	}
+	return graph
+}
+
+func TestBranchesFormTheMinimumSpanningForest(t *testing.T) {
+
+	var tests = []struct {
+		name     string
+		graph    *Graph
+		distinct bool // with distinct weights the minimum spanning forest is unique
+	}{
+		{"single edge", graphOf([][3]int{{0, 1, 3}}), true},
+		{"triangle", graphOf([][3]int{{0, 1, 3}, {1, 2, 1}, {0, 2, 2}}), true},
+		{"heavy edge bypassed", graphOf([][3]int{{0, 1, 4}, {0, 2, 1}, {2, 1, 2}, {1, 3, 5}, {2, 3, 8}, {3, 4, 3}}), true},
+		{"equal weights", graphOf([][3]int{{0, 1, 1}, {1, 2, 1}, {2, 3, 1}, {3, 0, 1}, {0, 2, 1}}), false},
+		{"two components", graphOf([][3]int{{0, 1, 2}, {1, 2, 1}, {0, 2, 3}, {3, 4, 5}, {4, 5, 4}}), true},
+		{"random dense", randomGraph(8, 0.7, 20, 1), false},
+		{"random sparse", randomGraph(10, 0.3, 5, 2), false},
+		{"random with few weights", randomGraph(9, 0.5, 2, 3), false},
+	}
+
+	for _, test := range tests {
+
+		var expected = test.graph.MinimumSpanningForest()
+		var components = len(test.graph.ConnectedComponents())
+
+		for seed := int64(0); seed < 10; seed++ {
+
+			// wake up every vertex, or only the first one if the graph is connected
+			var awake = test.graph.Vertices()
+			if components == 1 && seed%2 == 0 {
+
+				awake = awake[:1]
+			}
+
+			var completed, results = runGHS(test.graph, awake, seed)
+			var tree, branchError = branchesOf(test.graph, results)
+			if branchError != nil {
+
+				t.Errorf("%s, seed %d: %v", test.name, seed, branchError)
+				continue
+			}
+
+			if test.distinct {
+
+				if sorted := sortedEdges(tree); Sprint(sorted) != Sprint(sortedEdges(expected)) {
+
+					t.Errorf("%s, seed %d: branches %v, expected %v", test.name, seed, sorted, sortedEdges(expected))
+				}
+
+			} else if tree.TotalWeight() != expected.TotalWeight() || len(tree) != len(expected) {
+
+				t.Errorf("%s, seed %d: %d branches weigh %v, expected %d weighing %v", test.name, seed, len(tree), tree.TotalWeight(), len(expected), expected.TotalWeight())
+			}
+
+			// n - c edges without a cycle span every component
+			var forest = tree.Graph()
+			if len(tree) != forest.VertexCount()-len(forest.ConnectedComponents()) {
+
+				t.Errorf("%s, seed %d: branches %v contain a cycle", test.name, seed, tree)
+			}
+
+			// only one endpoint of the final core of every component reports
+			if len(completed) != components {
+
+				t.Errorf("%s, seed %d: %v completed, expected one node per component (%d)", test.name, seed, completed, components)
+			}
+		}
+	}
+}
+
+func TestIsolatedNodeHalts(t *testing.T) {
+
+	var graph = NewGraph()
+	graph.AddVertex(0)
+
+	var completed, results = runGHS(graph, []Vertex{0}, 0)
+	if !results[0].Halted || len(results[0].Branches) != 0 || len(completed) != 1 {
+
+		t.Errorf("isolated node has result %v and completed %v, expected it to halt alone", results[0], completed)
+	}
+}
+
+func TestEdgeWeightLess(t *testing.T) {
+
+	var tests = []struct {
+		lhs      EdgeWeight
+		rhs      EdgeWeight
+		expected bool
+	}{
+		{EdgeWeight{1, "a", "b"}, EdgeWeight{2, "a", "b"}, true},
+		{EdgeWeight{2, "a", "b"}, EdgeWeight{1, "a", "b"}, false},
+		{EdgeWeight{1, "a", "c"}, EdgeWeight{1, "b", "c"}, true},
+		{EdgeWeight{1, "a", "b"}, EdgeWeight{1, "a", "c"}, true},
+		{EdgeWeight{1, "a", "b"}, EdgeWeight{1, "a", "b"}, false},
+		{EdgeWeight{1, "a", "b"}, infinity, true},
+	}
+
+	for _, test := range tests {
+
+		if less := test.lhs.Less(test.rhs); less != test.expected {
+
+			t.Errorf("%v < %v is %v, expected %v", test.lhs, test.rhs, less, test.expected)
+		}
+	}
+}
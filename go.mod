@@ -0,0 +1,3 @@
+module github.com/DevAndArtist/Distributed-BFS-in-Go
+
+go 1.22
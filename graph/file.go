@@ -0,0 +1,104 @@
+//
+//  file.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+package graph
+
+import . "fmt"
+
+import "bufio"
+import "io"
+import "math"
+import "strconv"
+import "strings"
+
+// WriteGraph writes one edge per line as "from to weight", directed edges
+// are written as "from -> to weight".
+func WriteGraph(writer io.Writer, graph *Graph) error {
+
+	for _, edge := range graph.edges {
+
+		var format = "%d %d %g\n"
+		if edge.Directed {
+			format = "%d -> %d %g\n"
+		}
+
+		if _, writeError := Fprintf(writer, format, edge.From, edge.To, edge.Weight); writeError != nil {
+
+			return writeError
+		}
+	}
+	return nil
+}
+
+// ReadGraph reads the format of WriteGraph. The weight is optional and
+// defaults to 1, empty lines and lines starting with '#' are ignored. Every
+// edge becomes a link between two clients, so self-loops and a second edge
+// between the same vertices, in either direction, are rejected.
+func ReadGraph(reader io.Reader) (*Graph, error) {
+
+	var graph = NewGraph()
+	var scanner = bufio.NewScanner(reader)
+	var lines = make(map[[2]Vertex]int) // line of the edge between two vertices, the smaller one first
+
+	for line := 1; scanner.Scan(); line++ {
+
+		var fields = strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		var edge = Edge{Weight: 1}
+		if len(fields) > 1 && fields[1] == "->" {
+
+			edge.Directed = true
+			fields = append(fields[:1], fields[2:]...)
+		}
+
+		if len(fields) != 2 && len(fields) != 3 {
+
+			return nil, Errorf("line %d: expected \"from [->] to [weight]\", got %q", line, scanner.Text())
+		}
+
+		var from, fromError = strconv.Atoi(fields[0])
+		var to, toError = strconv.Atoi(fields[1])
+		if fromError != nil || toError != nil || from < 0 || to < 0 {
+
+			return nil, Errorf("line %d: invalid vertices %q", line, scanner.Text())
+		}
+		edge.From = Vertex(from)
+		edge.To = Vertex(to)
+
+		if edge.From == edge.To {
+
+			return nil, Errorf("line %d: self-loop at vertex %d", line, from)
+		}
+
+		var pair = [2]Vertex{edge.From, edge.To}
+		if pair[0] > pair[1] {
+
+			pair[0], pair[1] = pair[1], pair[0]
+		}
+		if previous, exists := lines[pair]; exists {
+
+			return nil, Errorf("line %d: vertices %d and %d are already connected in line %d", line, pair[0], pair[1], previous)
+		}
+		lines[pair] = line
+
+		if len(fields) == 3 {
+
+			var weight, weightError = strconv.ParseFloat(fields[2], 64)
+			if weightError != nil || weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+
+				return nil, Errorf("line %d: invalid weight %q", line, fields[2])
+			}
+			edge.Weight = Weight(weight)
+		}
+		graph.AddEdge(edge)
+	}
+	return graph, scanner.Err()
+}
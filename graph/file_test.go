@@ -0,0 +1,97 @@
+//
+//  file_test.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+package graph
+
+import "bytes"
+import "reflect"
+import "strings"
+import "testing"
+
+func TestGraphFileRoundTrip(t *testing.T) {
+
+	var tests = []struct {
+		name  string
+		edges EdgeList
+	}{
+		{"unweighted", EdgeList{{0, 1, 1, false}, {1, 2, 1, false}, {0, 2, 1, false}}},
+		{"weighted", EdgeList{{0, 1, 2.5, false}, {1, 2, 0, false}, {3, 2, 7, false}}},
+		{"directed", EdgeList{{0, 1, 1, true}, {2, 1, 4, true}, {2, 3, 1, false}}},
+		{"empty", EdgeList{}},
+	}
+
+	for _, test := range tests {
+
+		var buffer bytes.Buffer
+		if writeError := WriteGraph(&buffer, test.edges.Graph()); writeError != nil {
+
+			t.Errorf("%s: writing failed: %v", test.name, writeError)
+			continue
+		}
+
+		var graph, readError = ReadGraph(&buffer)
+		if readError != nil {
+
+			t.Errorf("%s: reading failed: %v", test.name, readError)
+			continue
+		}
+
+		if edges := graph.EdgeList(); !reflect.DeepEqual(edges, test.edges) {
+
+			t.Errorf("%s: read %v, expected %v", test.name, edges, test.edges)
+		}
+	}
+}
+
+func TestReadGraph(t *testing.T) {
+
+	var tests = []struct {
+		name     string
+		input    string
+		expected EdgeList
+		err      string
+	}{
+		{"default weight", "0 1\n1 2 3\n", EdgeList{{0, 1, 1, false}, {1, 2, 3, false}}, ""},
+		{"comments and empty lines", "# a comment\n\n0 -> 1\n  \n", EdgeList{{0, 1, 1, true}}, ""},
+		{"too few fields", "0 1\n2\n", nil, "line 2: expected"},
+		{"too many fields", "0 1 2 3\n", nil, "line 1: expected"},
+		{"invalid vertex", "0 a\n", nil, "line 1: invalid vertices"},
+		{"negative vertex", "0 -1\n", nil, "line 1: invalid vertices"},
+		{"invalid weight", "0 1 heavy\n", nil, "line 1: invalid weight"},
+		{"negative weight", "0 1 -2\n", nil, "line 1: invalid weight"},
+		{"NaN weight", "0 1\n1 2 NaN\n", nil, "line 2: invalid weight \"NaN\""},
+		{"infinite weight", "0 1 Inf\n", nil, "line 1: invalid weight \"Inf\""},
+		{"negative infinite weight", "0 1 -inf\n", nil, "line 1: invalid weight \"-inf\""},
+		{"self-loop", "0 1\n# two\n2 2\n", nil, "line 3: self-loop at vertex 2"},
+		{"parallel edge", "0 1\n1 2\n0 1 5\n", nil, "line 3: vertices 0 and 1 are already connected in line 1"},
+		{"reversed edge", "0 1\n1 0\n", nil, "line 2: vertices 0 and 1 are already connected in line 1"},
+		{"reversed directed edge", "3 -> 1\n\n1 -> 3\n", nil, "line 3: vertices 1 and 3 are already connected in line 1"},
+	}
+
+	for _, test := range tests {
+
+		var graph, err = ReadGraph(strings.NewReader(test.input))
+		if test.err != "" {
+
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+
+				t.Errorf("%s: got error %v, expected %q", test.name, err, test.err)
+			}
+			continue
+		}
+
+		if err != nil {
+
+			t.Errorf("%s: unexpected error %v", test.name, err)
+
+		} else if edges := graph.EdgeList(); !reflect.DeepEqual(edges, test.expected) {
+
+			t.Errorf("%s: read %v, expected %v", test.name, edges, test.expected)
+		}
+	}
+}
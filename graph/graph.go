@@ -6,15 +6,41 @@
 //  All rights reserved.
 //
 
+// Package graph generates and logs the random topologies wired up by the server.
 package graph
 
 import . "fmt"
 import "time"
+import "sort"
 import "math/rand"
 
-type Graph []Edge
-type Edge []Vertex
 type Vertex int
+type Weight float64
+
+// Edge connects two vertices, every edge of an unweighted graph has weight 1.
+// A directed edge may only be traversed from From to To.
+type Edge struct {
+	From     Vertex
+	To       Vertex
+	Weight   Weight
+	Directed bool
+}
+
+// EdgeList is the plain list form of a graph, e.g. as read from a file.
+type EdgeList []Edge
+
+// Graph keeps an adjacency list per vertex, so the neighbors of a vertex are
+// found without scanning every edge. An undirected edge is listed at both of
+// its endpoints, a directed one only at its From vertex.
+type Graph struct {
+	vertices  []Vertex             // kept in ascending order
+	adjacency map[Vertex][]Edge    // edges leaving the vertex, From is the vertex itself
+	degrees   map[Vertex]int       // number of edges incident to the vertex
+	weights   map[[2]Vertex]Weight // weight of every traversable (from, to) pair
+	edges     EdgeList             // every edge once, in the order it was added
+	directed  bool
+	weighted  bool
+}
 
 var seed *rand.Rand
 
@@ -24,27 +50,256 @@ func init() {
 	seed = rand.New(rand.NewSource(time.Now().UTC().UnixNano()))
 }
 
-func CreateRandomGraph(max int) Graph {
+func NewGraph() *Graph {
+
+	var graph = new(Graph)
+	graph.adjacency = make(map[Vertex][]Edge)
+	graph.degrees = make(map[Vertex]int)
+	graph.weights = make(map[[2]Vertex]Weight)
+	return graph
+}
+
+func CreateRandomGraph(max int) *Graph {
+
+	return CreateRandomWeightedGraph(max, 1)
+}
+
+// CreateRandomWeightedGraph creates a random graph like CreateRandomGraph,
+// every edge gets a random integral weight in [1, maxWeight].
+func CreateRandomWeightedGraph(max int, maxWeight int) *Graph {
+
+	// start with the complete graph and remove up to half of its edges
+	var edgeCount = max * (max - 1) / 2
+	var numberToRemove = seed.Intn(edgeCount/2) + 1
+
+	var removed = make(map[int]bool)
+	for len(removed) < numberToRemove {
+
+		removed[seed.Intn(edgeCount)] = true
+	}
 
-	var indexSlice Graph
+	var graph = NewGraph()
+	for index, i := 0, 0; i < max; i++ {
 
+		graph.AddVertex(Vertex(i))
+		for j := i + 1; j < max; j, index = j+1, index+1 {
+
+			var weight = Weight(seed.Intn(maxWeight) + 1)
+			if !removed[index] {
+
+				graph.AddEdge(Edge{From: Vertex(i), To: Vertex(j), Weight: weight})
+			}
+		}
+	}
+	return graph
+}
+
+// CreateRandomDirectedGraph creates a random weighted graph like
+// CreateRandomWeightedGraph and gives every edge a random direction.
+func CreateRandomDirectedGraph(max int, maxWeight int) *Graph {
+
+	return CreateRandomWeightedGraph(max, maxWeight).WithRandomDirections()
+}
+
+// CreateRandomSparseGraph creates a connected random graph whose vertices have
+// the given degree on average: a random spanning tree plus random edges. Unlike
+// the dense generators it scales to tens of thousands of vertices.
+func CreateRandomSparseGraph(max int, degree int, maxWeight int) *Graph {
+
+	var graph = NewGraph()
 	for i := 0; i < max; i++ {
 
-		for j := i + 1; j < max; j++ {
+		graph.AddVertex(Vertex(i))
+		if i > 0 {
+
+			graph.AddEdge(Edge{From: Vertex(seed.Intn(i)), To: Vertex(i), Weight: Weight(seed.Intn(maxWeight) + 1)})
+		}
+	}
+
+	var edgeCount = max * degree / 2
+	if maximum := max * (max - 1) / 2; edgeCount > maximum {
 
-			indexSlice = append(indexSlice, Edge{Vertex(i), Vertex(j)})
+		edgeCount = maximum
+	}
+
+	for graph.EdgeCount() < edgeCount {
+
+		var from, to = Vertex(seed.Intn(max)), Vertex(seed.Intn(max))
+		if from != to && !graph.HasEdge(from, to) {
+
+			graph.AddEdge(Edge{From: from, To: to, Weight: Weight(seed.Intn(maxWeight) + 1)})
 		}
 	}
+	return graph
+}
+
+// WithRandomDirections returns a copy of the graph in which every edge got a
+// random direction.
+func (graph *Graph) WithRandomDirections() *Graph {
+
+	var directed = NewGraph()
+	for _, vertex := range graph.Vertices() {
+
+		directed.AddVertex(vertex)
+	}
+
+	for _, edge := range graph.edges {
+
+		if seed.Intn(2) == 1 {
+
+			edge.From, edge.To = edge.To, edge.From
+		}
+		edge.Directed = true
+		directed.AddEdge(edge)
+	}
+	return directed
+}
 
-	var numberToRemove = seed.Intn(len(indexSlice)/2) + 1
+// AddVertex adds a vertex without edges, it returns false if it exists already.
+func (graph *Graph) AddVertex(vertex Vertex) bool {
 
-	for i := 0; i < numberToRemove; i++ {
+	if _, exists := graph.degrees[vertex]; exists {
 
-		var indexToRemove = seed.Intn(len(indexSlice))
-		indexSlice = append(indexSlice[:indexToRemove], indexSlice[indexToRemove+1:]...)
+		return false
 	}
 
-	return indexSlice
+	// the generators add the vertices in order, which only appends
+	var index = sort.Search(len(graph.vertices), func(i int) bool {
+
+		return graph.vertices[i] > vertex
+	})
+	graph.vertices = append(graph.vertices, vertex)
+	copy(graph.vertices[index+1:], graph.vertices[index:])
+	graph.vertices[index] = vertex
+	graph.degrees[vertex] = 0
+	return true
+}
+
+// AddEdge adds the edge and any of its endpoints the graph does not know yet.
+func (graph *Graph) AddEdge(edge Edge) {
+
+	graph.AddVertex(edge.From)
+	graph.AddVertex(edge.To)
+
+	graph.edges = append(graph.edges, edge)
+	graph.degrees[edge.From]++
+	graph.degrees[edge.To]++
+	graph.directed = graph.directed || edge.Directed
+	graph.weighted = graph.weighted || edge.Weight != 1
+
+	graph.addDirection(edge.From, edge.To, edge.Weight)
+	if !edge.Directed {
+
+		graph.addDirection(edge.To, edge.From, edge.Weight)
+	}
+}
+
+func (graph *Graph) addDirection(from Vertex, to Vertex, weight Weight) {
+
+	graph.adjacency[from] = append(graph.adjacency[from], Edge{From: from, To: to, Weight: weight})
+
+	// of parallel edges the lightest one counts
+	if existing, exists := graph.weights[[2]Vertex{from, to}]; !exists || weight < existing {
+
+		graph.weights[[2]Vertex{from, to}] = weight
+	}
+}
+
+// Vertices returns all vertices in ascending order.
+func (graph *Graph) Vertices() []Vertex {
+
+	return append([]Vertex{}, graph.vertices...)
+}
+
+func (graph *Graph) VertexCount() int {
+
+	return len(graph.vertices)
+}
+
+func (graph *Graph) EdgeCount() int {
+
+	return len(graph.edges)
+}
+
+// Neighbors returns the vertices that can be reached over one edge.
+func (graph *Graph) Neighbors(vertex Vertex) []Vertex {
+
+	var neighbors = make([]Vertex, 0, len(graph.adjacency[vertex]))
+	for _, edge := range graph.adjacency[vertex] {
+
+		neighbors = append(neighbors, edge.To)
+	}
+	return neighbors
+}
+
+// Degree returns the number of edges incident to the vertex, for directed
+// edges this is the sum of its in- and out-degree.
+func (graph *Graph) Degree(vertex Vertex) int {
+
+	return graph.degrees[vertex]
+}
+
+// HasEdge reports whether the edge can be traversed from one vertex to the other.
+func (graph *Graph) HasEdge(from Vertex, to Vertex) bool {
+
+	var _, exists = graph.weights[[2]Vertex{from, to}]
+	return exists
+}
+
+// WeightOf returns the weight of the edge between two vertices.
+func (graph *Graph) WeightOf(from Vertex, to Vertex) (Weight, bool) {
+
+	var weight, exists = graph.weights[[2]Vertex{from, to}]
+	return weight, exists
+}
+
+// IsDirected reports whether any edge is directed.
+func (graph *Graph) IsDirected() bool {
+
+	return graph.directed
+}
+
+// IsWeighted reports whether any edge has a weight other than 1.
+func (graph *Graph) IsWeighted() bool {
+
+	return graph.weighted
+}
+
+// EdgeList returns every edge once, in the order they were added.
+func (graph *Graph) EdgeList() EdgeList {
+
+	return append(EdgeList{}, graph.edges...)
+}
+
+// Graph builds the adjacency lists of the edges.
+func (edges EdgeList) Graph() *Graph {
+
+	var graph = NewGraph()
+	for _, edge := range edges {
+
+		graph.AddEdge(edge)
+	}
+	return graph
+}
+
+// TotalWeight returns the sum of all edge weights.
+func (edges EdgeList) TotalWeight() Weight {
+
+	var total Weight
+	for _, edge := range edges {
+
+		total += edge.Weight
+	}
+	return total
+}
+
+func (edge Edge) String() string {
+
+	var arrow = "--"
+	if edge.Directed {
+		arrow = "->"
+	}
+	return Sprintf("%d %s %d (%g)", edge.From, arrow, edge.To, edge.Weight)
 }
 
 func LogGraph(graph *Graph) {
@@ -53,15 +308,37 @@ func LogGraph(graph *Graph) {
 	Println("[Log] [Comment]: Use the following code at https://develop.open.wolframcloud.com -> 'Create a New Notebook'")
 	Printf("[Log] [Graph] [Code]: GraphPlot[{")
 
-	var length = len(*graph)
+	var length = graph.EdgeCount()
+	var weighted = graph.IsWeighted()
+
+	var options = "VertexLabeling -> True"
+	if weighted {
+		options += ", EdgeLabeling -> True"
+	}
+	if graph.IsDirected() {
+		options += ", DirectedEdges -> True"
+	}
+
+	if length == 0 {
 
-	for index, edge := range *graph {
+		Printf("}, %s]\n", options)
+	}
+
+	for index, edge := range graph.edges {
 
-		var separator = "}, VertexLabeling -> True]\n"
+		var separator = "}, " + options + "]\n"
 
 		if index < (length - 1) {
 			separator = ", "
 		}
-		Printf("%d -> %d%s", edge[0], edge[1], separator)
+
+		if weighted {
+
+			Printf("{%d -> %d, %g}%s", edge.From, edge.To, edge.Weight, separator)
+
+		} else {
+
+			Printf("%d -> %d%s", edge.From, edge.To, separator)
+		}
 	}
 }
@@ -0,0 +1,257 @@
+//
+//  reference.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+package graph
+
+import "container/heap"
+import "sort"
+
+// The centralized algorithms in this file are the reference the distributed
+// results are verified against. Directed edges are only followed from From to
+// To, except by ConnectedComponents which looks at the underlying undirected
+// graph.
+
+// HopDistances runs a breadth-first search and returns the number of hops
+// from the root to every vertex reachable from it.
+func (graph *Graph) HopDistances(root Vertex) map[Vertex]int {
+
+	var distances = map[Vertex]int{root: 0}
+	var queue = []Vertex{root}
+
+	for len(queue) > 0 {
+
+		var vertex = queue[0]
+		queue = queue[1:]
+		for _, edge := range graph.adjacency[vertex] {
+
+			if _, visited := distances[edge.To]; !visited {
+
+				distances[edge.To] = distances[vertex] + 1
+				queue = append(queue, edge.To)
+			}
+		}
+	}
+	return distances
+}
+
+// Eccentricity returns the largest number of hops from the vertex to any
+// vertex it can reach.
+func (graph *Graph) Eccentricity(vertex Vertex) int {
+
+	var eccentricity = 0
+	for _, distance := range graph.HopDistances(vertex) {
+
+		if distance > eccentricity {
+
+			eccentricity = distance
+		}
+	}
+	return eccentricity
+}
+
+// Diameter returns the largest eccentricity, i.e. the longest shortest path
+// in hops between two vertices that are connected at all.
+func (graph *Graph) Diameter() int {
+
+	var diameter = 0
+	for _, vertex := range graph.Vertices() {
+
+		if eccentricity := graph.Eccentricity(vertex); eccentricity > diameter {
+
+			diameter = eccentricity
+		}
+	}
+	return diameter
+}
+
+// ConnectedComponents returns the components of the graph, each sorted and
+// ordered by their smallest vertex. Vertices without edges form a component
+// on their own.
+func (graph *Graph) ConnectedComponents() [][]Vertex {
+
+	var components = newUnionFind()
+	for _, edge := range graph.edges {
+
+		components.union(edge.From, edge.To)
+	}
+
+	var indices = make(map[Vertex]int)
+	var result [][]Vertex
+	for _, vertex := range graph.Vertices() {
+
+		var root = components.find(vertex)
+		var index, known = indices[root]
+		if !known {
+
+			index = len(result)
+			indices[root] = index
+			result = append(result, nil)
+		}
+		result[index] = append(result[index], vertex)
+	}
+	return result
+}
+
+// ShortestDistances runs Dijkstra's algorithm and returns the distance of
+// every vertex reachable from the root.
+func (graph *Graph) ShortestDistances(root Vertex) map[Vertex]Weight {
+
+	var distances, _ = graph.dijkstra(root)
+	return distances
+}
+
+// ShortestPath returns the vertices of a shortest path from one vertex to
+// another, both included, and its length.
+func (graph *Graph) ShortestPath(from Vertex, to Vertex) ([]Vertex, Weight, bool) {
+
+	var distances, previous = graph.dijkstra(from)
+	var distance, reachable = distances[to]
+	if !reachable {
+
+		return nil, 0, false
+	}
+
+	var path = []Vertex{to}
+	for vertex := to; vertex != from; {
+
+		vertex = previous[vertex]
+		path = append(path, vertex)
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, distance, true
+}
+
+func (graph *Graph) dijkstra(root Vertex) (map[Vertex]Weight, map[Vertex]Vertex) {
+
+	var distances = map[Vertex]Weight{root: 0}
+	var previous = make(map[Vertex]Vertex)
+	var done = make(map[Vertex]bool)
+	var queue = &distanceQueue{{vertex: root, distance: 0}}
+
+	for queue.Len() > 0 {
+
+		var closest = heap.Pop(queue).(queuedVertex)
+		if done[closest.vertex] {
+			continue // an outdated entry, the vertex was queued again with a shorter distance
+		}
+		done[closest.vertex] = true
+
+		for _, edge := range graph.adjacency[closest.vertex] {
+
+			var distance, known = distances[edge.To]
+			if !known || closest.distance+edge.Weight < distance {
+
+				distances[edge.To] = closest.distance + edge.Weight
+				previous[edge.To] = closest.vertex
+				heap.Push(queue, queuedVertex{vertex: edge.To, distance: distances[edge.To]})
+			}
+		}
+	}
+	return distances, previous
+}
+
+// MinimumSpanningForest runs Kruskal's algorithm and returns the edges of a
+// minimum spanning tree of every connected component.
+func (graph *Graph) MinimumSpanningForest() EdgeList {
+
+	var edges = graph.EdgeList()
+	sort.SliceStable(edges, func(i int, j int) bool {
+
+		return edges[i].Weight < edges[j].Weight
+	})
+
+	var components = newUnionFind()
+	var forest = EdgeList{}
+	for _, edge := range edges {
+
+		if components.union(edge.From, edge.To) {
+
+			forest = append(forest, edge)
+		}
+	}
+	return forest
+}
+
+// unionFind tracks disjoint sets of vertices, with path halving.
+type unionFind map[Vertex]Vertex
+
+func newUnionFind() unionFind {
+
+	return make(unionFind)
+}
+
+func (sets unionFind) find(vertex Vertex) Vertex {
+
+	for {
+
+		var parent, known = sets[vertex]
+		if !known || parent == vertex {
+
+			return vertex
+		}
+
+		if grandparent, known := sets[parent]; known {
+
+			sets[vertex] = grandparent
+		}
+		vertex = parent
+	}
+}
+
+// union merges the sets of both vertices and returns false if they were one already.
+func (sets unionFind) union(lhs Vertex, rhs Vertex) bool {
+
+	var lhsRoot, rhsRoot = sets.find(lhs), sets.find(rhs)
+	if lhsRoot == rhsRoot {
+
+		return false
+	}
+	sets[lhsRoot] = rhsRoot
+	return true
+}
+
+// queuedVertex is an entry of the priority queue used by Dijkstra's algorithm.
+type queuedVertex struct {
+	vertex   Vertex
+	distance Weight
+}
+
+// distanceQueue implements heap.Interface, the closest vertex comes first.
+type distanceQueue []queuedVertex
+
+func (queue distanceQueue) Len() int {
+
+	return len(queue)
+}
+
+func (queue distanceQueue) Less(i int, j int) bool {
+
+	return queue[i].distance < queue[j].distance
+}
+
+func (queue distanceQueue) Swap(i int, j int) {
+
+	queue[i], queue[j] = queue[j], queue[i]
+}
+
+func (queue *distanceQueue) Push(element interface{}) {
+
+	*queue = append(*queue, element.(queuedVertex))
+}
+
+func (queue *distanceQueue) Pop() interface{} {
+
+	var old = *queue
+	var element = old[len(old)-1]
+	*queue = old[:len(old)-1]
+	return element
+}
@@ -0,0 +1,173 @@
+//
+//  reference_test.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+package graph
+
+import "reflect"
+import "testing"
+
+// weightedGraph is a triangle 0, 1, 2 whose heavy edge 0--1 is bypassed over
+// 2, with a tail 1--3--4 and a heavier shortcut 2--3.
+var weightedGraph = EdgeList{
+	{0, 1, 4, false}, {0, 2, 1, false}, {2, 1, 2, false},
+	{1, 3, 5, false}, {2, 3, 8, false}, {3, 4, 3, false},
+}
+
+// 0 -> 1 -> 2   5 --- 6   9
+var directedGraph = func() *Graph {
+
+	var graph = EdgeList{{0, 1, 1, true}, {1, 2, 1, true}, {5, 6, 1, false}}.Graph()
+	graph.AddVertex(9)
+	return graph
+}()
+
+func TestHopDistances(t *testing.T) {
+
+	var tests = []struct {
+		name     string
+		graph    *Graph
+		root     Vertex
+		expected map[Vertex]int
+	}{
+		{"weights are ignored", weightedGraph.Graph(), 0, map[Vertex]int{0: 0, 1: 1, 2: 1, 3: 2, 4: 3}},
+		{"from a leaf", weightedGraph.Graph(), 4, map[Vertex]int{4: 0, 3: 1, 1: 2, 2: 2, 0: 3}},
+		{"along directed edges", directedGraph, 0, map[Vertex]int{0: 0, 1: 1, 2: 2}},
+		{"against directed edges", directedGraph, 2, map[Vertex]int{2: 0}},
+		{"isolated vertex", directedGraph, 9, map[Vertex]int{9: 0}},
+	}
+
+	for _, test := range tests {
+
+		if distances := test.graph.HopDistances(test.root); !reflect.DeepEqual(distances, test.expected) {
+
+			t.Errorf("%s: got %v, expected %v", test.name, distances, test.expected)
+		}
+	}
+}
+
+func TestEccentricityAndDiameter(t *testing.T) {
+
+	var graph = weightedGraph.Graph()
+	var tests = []struct {
+		name     string
+		actual   int
+		expected int
+	}{
+		{"eccentricity of 0", graph.Eccentricity(0), 3},
+		{"eccentricity of 1", graph.Eccentricity(1), 2},
+		{"eccentricity of 4", graph.Eccentricity(4), 3},
+		{"diameter", graph.Diameter(), 3},
+		{"directed eccentricity of 2", directedGraph.Eccentricity(2), 0},
+		{"directed diameter", directedGraph.Diameter(), 2},
+		{"path diameter", EdgeList{{0, 1, 1, false}, {1, 2, 1, false}, {2, 3, 1, false}}.Graph().Diameter(), 3},
+	}
+
+	for _, test := range tests {
+
+		if test.actual != test.expected {
+
+			t.Errorf("%s: got %d, expected %d", test.name, test.actual, test.expected)
+		}
+	}
+}
+
+func TestConnectedComponents(t *testing.T) {
+
+	var tests = []struct {
+		name     string
+		graph    *Graph
+		expected [][]Vertex
+	}{
+		{"connected", weightedGraph.Graph(), [][]Vertex{{0, 1, 2, 3, 4}}},
+		{"directed edges count in both directions", directedGraph, [][]Vertex{{0, 1, 2}, {5, 6}, {9}}},
+		{"ordered by smallest vertex", EdgeList{{7, 3, 1, false}, {1, 8, 1, false}, {2, 7, 1, false}}.Graph(), [][]Vertex{{1, 8}, {2, 3, 7}}},
+	}
+
+	for _, test := range tests {
+
+		if components := test.graph.ConnectedComponents(); !reflect.DeepEqual(components, test.expected) {
+
+			t.Errorf("%s: got %v, expected %v", test.name, components, test.expected)
+		}
+	}
+}
+
+func TestShortestDistances(t *testing.T) {
+
+	var tests = []struct {
+		name     string
+		graph    *Graph
+		root     Vertex
+		expected map[Vertex]Weight
+	}{
+		{"lighter detour", weightedGraph.Graph(), 0, map[Vertex]Weight{0: 0, 1: 3, 2: 1, 3: 8, 4: 11}},
+		{"from a leaf", weightedGraph.Graph(), 4, map[Vertex]Weight{0: 11, 1: 8, 2: 10, 3: 3, 4: 0}},
+		{"against directed edges", directedGraph, 1, map[Vertex]Weight{1: 0, 2: 1}},
+	}
+
+	for _, test := range tests {
+
+		if distances := test.graph.ShortestDistances(test.root); !reflect.DeepEqual(distances, test.expected) {
+
+			t.Errorf("%s: got %v, expected %v", test.name, distances, test.expected)
+		}
+	}
+}
+
+func TestShortestPath(t *testing.T) {
+
+	var tests = []struct {
+		name      string
+		graph     *Graph
+		from      Vertex
+		to        Vertex
+		path      []Vertex
+		distance  Weight
+		reachable bool
+	}{
+		{"across the graph", weightedGraph.Graph(), 0, 4, []Vertex{0, 2, 1, 3, 4}, 11, true},
+		{"lighter detour", weightedGraph.Graph(), 0, 1, []Vertex{0, 2, 1}, 3, true},
+		{"to itself", weightedGraph.Graph(), 3, 3, []Vertex{3}, 0, true},
+		{"along directed edges", directedGraph, 0, 2, []Vertex{0, 1, 2}, 2, true},
+		{"against directed edges", directedGraph, 2, 0, nil, 0, false},
+		{"other component", directedGraph, 0, 5, nil, 0, false},
+	}
+
+	for _, test := range tests {
+
+		var path, distance, reachable = test.graph.ShortestPath(test.from, test.to)
+		if !reflect.DeepEqual(path, test.path) || distance != test.distance || reachable != test.reachable {
+
+			t.Errorf("%s: got (%v, %v, %v), expected (%v, %v, %v)", test.name, path, distance, reachable, test.path, test.distance, test.reachable)
+		}
+	}
+}
+
+func TestMinimumSpanningForest(t *testing.T) {
+
+	var tests = []struct {
+		name        string
+		graph       *Graph
+		edges       EdgeList
+		totalWeight Weight
+	}{
+		{"connected", weightedGraph.Graph(), EdgeList{{0, 2, 1, false}, {2, 1, 2, false}, {3, 4, 3, false}, {1, 3, 5, false}}, 11},
+		{"one tree per component", EdgeList{{0, 1, 2, false}, {1, 2, 1, false}, {0, 2, 3, false}, {5, 6, 4, false}}.Graph(),
+			EdgeList{{1, 2, 1, false}, {0, 1, 2, false}, {5, 6, 4, false}}, 7},
+		{"no edges", NewGraph(), EdgeList{}, 0},
+	}
+
+	for _, test := range tests {
+
+		var forest = test.graph.MinimumSpanningForest()
+		if !reflect.DeepEqual(forest, test.edges) || forest.TotalWeight() != test.totalWeight {
+
+			t.Errorf("%s: got %v with weight %v, expected %v with weight %v", test.name, forest, forest.TotalWeight(), test.edges, test.totalWeight)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+//
+//  stats.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+package graph
+
+import . "fmt"
+
+import "sort"
+import "strings"
+
+// exactDiameterLimit is the largest number of adjacency steps for which Stats
+// computes the exact diameter. It needs a breadth-first search per vertex, each
+// visits every vertex and follows every edge in both directions.
+const exactDiameterLimit = 100000000
+
+// Stats summarizes the shape of a graph.
+type Stats struct {
+	Vertices      int
+	Edges         int
+	Directed      bool
+	Density       float64 // edges relative to the edges of the complete graph
+	MinDegree     int
+	MaxDegree     int
+	MeanDegree    float64
+	Degrees       map[int]int // number of vertices per degree
+	Components    int
+	Diameter      int
+	DiameterExact bool // false for large graphs, Diameter is a lower bound then
+}
+
+func (graph *Graph) Stats() Stats {
+
+	var stats = Stats{Vertices: graph.VertexCount(), Edges: graph.EdgeCount(), Directed: graph.IsDirected()}
+	stats.Degrees = make(map[int]int)
+
+	if stats.Vertices > 1 {
+
+		var possibleEdges = float64(stats.Vertices) * float64(stats.Vertices-1)
+		if !stats.Directed {
+
+			possibleEdges /= 2
+		}
+		stats.Density = float64(stats.Edges) / possibleEdges
+	}
+
+	for index, vertex := range graph.Vertices() {
+
+		var degree = graph.Degree(vertex)
+		stats.Degrees[degree]++
+		stats.MeanDegree += float64(degree)
+
+		if index == 0 || degree < stats.MinDegree {
+
+			stats.MinDegree = degree
+		}
+		if degree > stats.MaxDegree {
+
+			stats.MaxDegree = degree
+		}
+	}
+
+	if stats.Vertices > 0 {
+
+		stats.MeanDegree /= float64(stats.Vertices)
+	}
+	stats.Components = len(graph.ConnectedComponents())
+
+	var steps = int64(stats.Vertices) * int64(stats.Vertices+2*stats.Edges)
+	if steps <= exactDiameterLimit {
+
+		stats.Diameter = graph.Diameter()
+		stats.DiameterExact = true
+
+	} else {
+
+		stats.Diameter = graph.diameterLowerBound()
+	}
+	return stats
+}
+
+// diameterLowerBound runs a double sweep from the first vertex of every
+// component: the eccentricity of the farthest vertex found is a lower bound
+// of the diameter, and usually a tight one.
+func (graph *Graph) diameterLowerBound() int {
+
+	var bound = 0
+	for _, component := range graph.ConnectedComponents() {
+
+		var farthest, distance = component[0], 0
+		for vertex, hops := range graph.HopDistances(component[0]) {
+
+			if hops > distance {
+
+				farthest, distance = vertex, hops
+			}
+		}
+
+		if eccentricity := graph.Eccentricity(farthest); eccentricity > bound {
+
+			bound = eccentricity
+		}
+		if distance > bound {
+
+			bound = distance
+		}
+	}
+	return bound
+}
+
+// DegreeDistribution lists the number of vertices per degree as "{degree: vertices, ...}".
+func (stats Stats) DegreeDistribution() string {
+
+	var degrees []int
+	for degree := range stats.Degrees {
+
+		degrees = append(degrees, degree)
+	}
+	sort.Ints(degrees)
+
+	var distribution []string
+	for _, degree := range degrees {
+
+		distribution = append(distribution, Sprintf("%d: %d", degree, stats.Degrees[degree]))
+	}
+	return "{" + strings.Join(distribution, ", ") + "}"
+}
+
+func (stats Stats) String() string {
+
+	var diameter = Sprintf("%d", stats.Diameter)
+	if !stats.DiameterExact {
+
+		diameter = ">= " + diameter
+	}
+
+	return Sprintf("<Vertices: %d Edges: %d Directed: %t Density: %.3f Degree: %d..%d (mean %.2f) Components: %d Diameter: %s Distribution: %s>",
+		stats.Vertices, stats.Edges, stats.Directed, stats.Density, stats.MinDegree, stats.MaxDegree, stats.MeanDegree,
+		stats.Components, diameter, stats.DegreeDistribution())
+}
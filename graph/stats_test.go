@@ -0,0 +1,44 @@
+//
+//  stats_test.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+package graph
+
+import "testing"
+
+func pathGraph(vertices int) *Graph {
+
+	var edges = EdgeList{}
+	for vertex := 1; vertex < vertices; vertex++ {
+
+		edges = append(edges, Edge{Vertex(vertex - 1), Vertex(vertex), 1, false})
+	}
+	return edges.Graph()
+}
+
+func TestStatsDiameter(t *testing.T) {
+
+	var tests = []struct {
+		name     string
+		graph    *Graph
+		diameter int
+		exact    bool
+	}{
+		{"small path", pathGraph(10), 9, true},
+		{"cycle", EdgeList{{0, 1, 1, false}, {1, 2, 1, false}, {2, 3, 1, false}, {3, 0, 1, false}}.Graph(), 2, true},
+		{"path beyond the step limit", pathGraph(10000), 9999, false}, // 10^4 * 3 * 10^4 steps
+	}
+
+	for _, test := range tests {
+
+		var stats = test.graph.Stats()
+		if stats.Diameter != test.diameter || stats.DiameterExact != test.exact {
+
+			t.Errorf("%s: got diameter %d (exact %v), expected %d (exact %v)", test.name, stats.Diameter, stats.DiameterExact, test.diameter, test.exact)
+		}
+	}
+}
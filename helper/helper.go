@@ -6,6 +6,7 @@
 //  All rights reserved.
 //
 
+// Package helper contains small utilities shared by the server and the clients.
 package helper
 
 import . "fmt"
@@ -6,9 +6,13 @@
 //  All rights reserved.
 //
 
+// Package identification describes how a client introduces itself to others.
 package identification
 
 type Identification struct {
-	ID      string
-	Address string
+	ID       string
+	Address  string
+	Weight   float64 // weight of the link to this client, set by the server with NewNeighborCommand
+	Directed bool    // the link may only carry messages from the dialing client to this one
+	Epoch    uint64  // number of times the dialing client reconnected the link, 0 on the first connection
 }
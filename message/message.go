@@ -6,11 +6,29 @@
 //  All rights reserved.
 //
 
+// Package message defines the envelope sent between the server and the clients.
 package message
 
+import "encoding/gob"
+
 type Message struct {
 	Sender   string
 	Receiver string
 	Command  uint8
 	Value    interface{}
+	Instance string // traversal instance, empty for the default one
+	Sequence uint64 // number of the message on its link between two clients, 0 otherwise
+}
+
+// Relay is the value of RelayCommand: the server forwards the wrapped message
+// to its receiver, e.g. to answer a predecessor on a directed link.
+type Relay struct {
+	Receiver string
+	Command  uint8
+	Value    interface{}
+}
+
+func init() {
+
+	gob.Register(Relay{})
 }
@@ -0,0 +1,161 @@
+//
+//  reach.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+// Package reach finds the nodes the root can reach over directed links.
+package reach
+
+import . "fmt"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/bfs/command"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/message"
+
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/algorithm"
+
+import "sync"
+import "encoding/gob"
+
+// Node floods ReachCommand along its outgoing links only. Termination is
+// detected with Dijkstra-Scholten, but a successor cannot answer over a
+// directed link, so acknowledgements take the detour through the server with
+// RelayCommand.
+type Node struct {
+	guard       sync.Mutex
+	host        algorithm.Host
+	id          string
+	parentID    string
+	root        bool
+	successors  []string
+	engagedWith string // sender of the reach message we have not acknowledged yet
+	deficit     int    // number of own reach messages not acknowledged yet
+	completed   bool
+	messages    int
+}
+
+type Result struct {
+	ID       string
+	ParentID string // empty if the root cannot reach the node
+	Messages int
+}
+
+func init() {
+
+	gob.Register(Result{})
+	algorithm.Register("reach", func() algorithm.Algorithm { return new(Node) })
+}
+
+func (node *Node) Init(environment algorithm.Environment) {
+
+	node.guard.Lock()
+	node.host = environment.Host
+	node.id = environment.ID
+	node.parentID = ""
+	node.successors = append([]string{}, environment.Neighbors...)
+	node.guard.Unlock()
+}
+
+func (node *Node) SupportsDirectedLinks() bool {
+
+	return true
+}
+
+func (node *Node) HandleMessage(sender string, receiver string, command uint8, value interface{}) {
+
+	node.guard.Lock()
+	switch command {
+
+	case InitCommand:
+		node.root = true
+		node.parentID = node.id
+		node.broadcast()
+
+	case ReachCommand:
+		if len(node.parentID) == 0 {
+
+			node.parentID = sender
+			node.engagedWith = sender
+			node.broadcast()
+
+		} else {
+
+			node.acknowledge(sender)
+		}
+
+	case ReachAckCommand:
+		node.deficit--
+
+	default:
+		Printf("[Reach Algorithm]: Unknown command \"%d\"- do nothing\n", command)
+	}
+	node.checkTermination()
+	node.guard.Unlock()
+}
+
+func (node *Node) Result() interface{} {
+
+	node.guard.Lock()
+	var result = Result{ID: node.id, ParentID: node.parentID, Messages: node.messages}
+	node.guard.Unlock()
+	return result
+}
+
+// The following helpers expect the guard to be locked by the caller.
+func (node *Node) broadcast() {
+
+	for _, successorID := range node.successors {
+
+		node.deficit++
+		node.send(successorID, ReachCommand, nil)
+	}
+}
+
+func (node *Node) acknowledge(predecessorID string) {
+
+	node.send("server", RelayCommand, Relay{Receiver: predecessorID, Command: ReachAckCommand})
+}
+
+func (node *Node) checkTermination() {
+
+	if node.deficit > 0 {
+		return
+	}
+
+	if len(node.engagedWith) > 0 {
+
+		node.acknowledge(node.engagedWith)
+		node.engagedWith = ""
+
+	} else if node.root && !node.completed {
+
+		node.completed = true
+		node.send("server", CompleteCommand, nil)
+	}
+}
+
+func (node *Node) send(receiver string, command uint8, value interface{}) {
+
+	node.messages++
+	node.host.SendMessage(node.id, receiver, command, value)
+}
+
+func (result Result) Parent() string {
+
+	return result.ParentID
+}
+
+func (result Result) MessageCount() int {
+
+	return result.Messages
+}
+
+func (result Result) String() string {
+
+	if len(result.ParentID) == 0 {
+
+		return Sprintf("<ID: %s unreachable>", result.ID)
+	}
+	return Sprintf("<ID: %s Parent: %s>", result.ID, result.ParentID)
+}
@@ -0,0 +1,150 @@
+//
+//  reach_test.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+package reach
+
+import . "fmt"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/bfs/command"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/graph"
+
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/algorithm"
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/algorithm/algorithmtest"
+
+import "math/rand"
+import "testing"
+
+var idOf = algorithmtest.IDOf
+
+// directedGraphOf creates a graph of directed {from, to} edges.
+func directedGraphOf(edges [][2]int) *Graph {
+
+	var graph = NewGraph()
+	for _, edge := range edges {
+
+		graph.AddEdge(Edge{From: Vertex(edge[0]), To: Vertex(edge[1]), Weight: 1, Directed: true})
+	}
+	return graph
+}
+
+// randomDirectedGraph gives the edges of a random graph reproducible directions.
+func randomDirectedGraph(vertices int, extraEdges int, seed int64) *Graph {
+
+	var random = rand.New(rand.NewSource(seed))
+	var graph = NewGraph()
+	for _, edge := range algorithmtest.RandomGraph(vertices, extraEdges, 1, seed).EdgeList() {
+
+		if random.Intn(2) == 1 {
+
+			edge.From, edge.To = edge.To, edge.From
+		}
+		edge.Directed = true
+		graph.AddEdge(edge)
+	}
+	return graph
+}
+
+// flood starts at the root and delivers every message, the acknowledgements
+// take the detour through the server.
+func flood(graph *Graph, root Vertex, seed int64) ([]algorithmtest.Sent, map[Vertex]*Node) {
+
+	var network = algorithmtest.NetworkWith(seed)
+	var nodes = make(map[Vertex]*Node)
+	for vertex, node := range algorithmtest.NodesOf(graph, network, func() algorithm.Algorithm { return new(Node) }) {
+
+		nodes[vertex] = node.(*Node)
+	}
+
+	network.SendMessage("server", idOf(root), InitCommand, nil)
+	network.Deliver()
+	return network.ServerMessages(), nodes
+}
+
+func TestNodeFindsTheReachableVertices(t *testing.T) {
+
+	var tests = []struct {
+		name  string
+		graph *Graph
+	}{
+		{"single edge", directedGraphOf([][2]int{{0, 1}})},
+		{"edge into the root", directedGraphOf([][2]int{{1, 0}})},
+		{"path", directedGraphOf([][2]int{{0, 1}, {1, 2}, {2, 3}})},
+		{"cycle", directedGraphOf([][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 0}})},
+		{"diamond with a dead end", directedGraphOf([][2]int{{0, 1}, {0, 2}, {1, 3}, {2, 3}, {4, 3}, {4, 0}})},
+		{"two cycles", directedGraphOf([][2]int{{0, 1}, {1, 0}, {1, 2}, {2, 3}, {3, 2}, {3, 4}, {5, 4}})},
+		{"random", randomDirectedGraph(12, 10, 1)},
+		{"random dense", randomDirectedGraph(10, 25, 2)},
+	}
+
+	for _, test := range tests {
+
+		for seed := int64(0); seed < 10; seed++ {
+
+			var name = Sprintf("%s, seed %d", test.name, seed)
+			var server, nodes = flood(test.graph, 0, seed)
+
+			// Dijkstra-Scholten: once the root knows, every reach message was acknowledged
+			if len(server) != 1 || server[0].Sender != idOf(0) || server[0].Command != CompleteCommand {
+
+				t.Errorf("%s: the server received %v, expected a single completion of the root", name, server)
+
+			} else if server[0].InFlight != 0 {
+
+				t.Errorf("%s: the root completed with %d messages in flight", name, server[0].InFlight)
+			}
+
+			var vertices = make(map[string]Vertex)
+			for _, vertex := range test.graph.Vertices() {
+
+				vertices[idOf(vertex)] = vertex
+			}
+
+			// every reached vertex floods its successors once and every reach
+			// message is acknowledged once
+			var distances = test.graph.HopDistances(0)
+			var expected, messages = 1, 0
+			for vertex, node := range nodes {
+
+				var result = node.Result().(Result)
+				var _, reachable = distances[vertex]
+				messages += result.Messages
+
+				if node.deficit != 0 || node.engagedWith != "" {
+
+					t.Errorf("%s: vertex %d ends with deficit %d engaged with %q", name, vertex, node.deficit, node.engagedWith)
+				}
+
+				if !reachable {
+
+					if result.ParentID != "" {
+
+						t.Errorf("%s: unreachable vertex %d has parent %s", name, vertex, result.ParentID)
+					}
+					continue
+				}
+				expected += 2 * len(test.graph.Neighbors(vertex))
+
+				if vertex == 0 {
+
+					if result.ParentID != idOf(0) {
+
+						t.Errorf("%s: root has parent %s", name, result.ParentID)
+					}
+
+				} else if parent, found := vertices[result.ParentID]; !found || !test.graph.HasEdge(parent, vertex) {
+
+					t.Errorf("%s: reachable vertex %d has parent %q, which is no predecessor", name, vertex, result.ParentID)
+				}
+			}
+
+			if messages != expected {
+
+				t.Errorf("%s: the nodes sent %d messages, expected %d", name, messages, expected)
+			}
+		}
+	}
+}
@@ -0,0 +1,374 @@
+//
+//  reliable.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+// Package reliable delivers messages over a link between two clients whose
+// connection may fail: every message gets a sequence number, the receiver
+// acknowledges it and the sender retransmits it until then, also over a new
+// connection after a reconnect. The receiver hands the messages out exactly
+// once and in the order they were sent.
+package reliable
+
+import . "fmt"
+import . "encoding/gob"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/message"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/bfs/command"
+
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/chaos"
+
+import "net"
+import "sort"
+import "sync"
+import "time"
+
+// RetransmitTimeout is how long a message may stay unacknowledged before it
+// is sent again.
+const RetransmitTimeout = 250 * time.Millisecond
+
+type Link struct {
+	guard    sync.Mutex
+	local    string
+	peer     string
+	writer   *writer // nil while the link waits for a reconnect
+	chaos    *chaos.Injector
+	sequence uint64                     // last sequence number sent
+	pending  map[uint64]*pendingMessage // sent, but not yet acknowledged
+	next     uint64                     // next sequence number to hand out
+	buffer   map[uint64]Message         // arrived ahead of next
+	epoch    uint64                     // epoch of the current connection
+	attached bool                       // a connection was attached at least once
+	stop     chan bool                  // closed by Close
+}
+
+type pendingMessage struct {
+	message Message
+	sentAt  time.Time
+}
+
+// writer encodes the messages of one connection on its own routine, so
+// neither the reader of the connection nor the callers of the link wait while
+// the peer does not read.
+type writer struct {
+	guard      sync.Mutex
+	peer       string
+	connection net.Conn
+	encoder    *Encoder
+	queue      []Message
+	queued     map[queueKey]bool // a message or ack already waiting in queue
+	wake       chan bool
+	stop       chan bool
+	once       sync.Once
+}
+
+type queueKey struct {
+	ack      bool
+	sequence uint64
+}
+
+// LinkBetween creates the link of the local client to a peer and starts
+// retransmitting. Faults of the injector apply to every message sent over the
+// link, including retransmissions and acknowledgements.
+func LinkBetween(local string, peer string, injector *chaos.Injector) *Link {
+
+	var link = new(Link)
+	link.local = local
+	link.peer = peer
+	link.chaos = injector
+	link.pending = make(map[uint64]*pendingMessage)
+	link.next = 1
+	link.buffer = make(map[uint64]Message)
+	link.stop = make(chan bool)
+
+	go link.retransmit()
+	return link
+}
+
+// Attach makes the connection the one the link sends over, any previous one
+// is closed. The encoder must be the only one written to the connection, e.g.
+// the one of the handshake. The epoch counts the reconnects of the link, a
+// connection whose epoch is not newer than the current one is refused, which
+// tells a reconnect apart from a second link to the same peer. Every
+// unacknowledged message is sent again right away.
+func (link *Link) Attach(connection net.Conn, encoder *Encoder, epoch uint64) bool {
+
+	link.guard.Lock()
+	if link.attached && epoch <= link.epoch {
+
+		link.guard.Unlock()
+		return false
+	}
+
+	if link.writer != nil {
+
+		link.writer.close()
+	}
+	link.writer = writerFor(link.peer, connection, encoder)
+	link.epoch = epoch
+	link.attached = true
+
+	var pending = link.unacknowledged(time.Now())
+	link.guard.Unlock()
+
+	for _, message := range pending {
+
+		link.transmit(message)
+	}
+	return true
+}
+
+// Epoch returns the epoch of the current connection.
+func (link *Link) Epoch() uint64 {
+
+	link.guard.Lock()
+	defer link.guard.Unlock()
+	return link.epoch
+}
+
+// Close stops retransmitting and closes the current connection.
+func (link *Link) Close() {
+
+	link.guard.Lock()
+	defer link.guard.Unlock()
+
+	select {
+	case <-link.stop:
+		return // closed already
+	default:
+	}
+	close(link.stop)
+
+	if link.writer != nil {
+
+		link.writer.close()
+		link.writer = nil
+	}
+}
+
+// Detach is called when reading from the connection failed. It returns
+// false if the link moved on to another connection already.
+func (link *Link) Detach(connection net.Conn) bool {
+
+	link.guard.Lock()
+	defer link.guard.Unlock()
+
+	if link.writer == nil || link.writer.connection != connection {
+
+		return false
+	}
+	link.writer.close()
+	link.writer = nil
+	return true
+}
+
+// Reset closes the current connection, the reading side notices and reconnects.
+func (link *Link) Reset() {
+
+	link.guard.Lock()
+	if link.writer != nil {
+
+		link.writer.connection.Close()
+	}
+	link.guard.Unlock()
+}
+
+// Send numbers the message and sends it, it is retransmitted until the peer
+// acknowledged it.
+func (link *Link) Send(message Message) {
+
+	link.guard.Lock()
+	link.sequence++
+	message.Sequence = link.sequence
+	link.pending[message.Sequence] = &pendingMessage{message: message, sentAt: time.Now()}
+	link.guard.Unlock()
+
+	link.transmit(message)
+}
+
+// Receive takes a message read from the connection and returns the messages
+// that are ready to be handled, in the order they were sent. Acknowledgements
+// and duplicates yield none.
+func (link *Link) Receive(message Message) []Message {
+
+	if message.Command == AckCommand {
+
+		link.guard.Lock()
+		delete(link.pending, message.Sequence)
+		link.guard.Unlock()
+		return nil
+	}
+
+	if message.Sequence == 0 {
+
+		return []Message{message} // not sent over a link
+	}
+
+	// acknowledge every copy, the acknowledgement of the first one may be lost
+	link.transmit(Message{Sender: link.local, Receiver: link.peer, Command: AckCommand, Sequence: message.Sequence, Instance: message.Instance})
+
+	link.guard.Lock()
+	defer link.guard.Unlock()
+
+	if _, buffered := link.buffer[message.Sequence]; buffered || message.Sequence < link.next {
+
+		Printf("[Log] [Reliable]: dropping duplicate <%s> #%d from <%s>\n", StringFor(message.Command), message.Sequence, link.peer)
+		return nil
+	}
+	link.buffer[message.Sequence] = message
+
+	var ready []Message
+	for {
+
+		var next, arrived = link.buffer[link.next]
+		if !arrived {
+			break
+		}
+		delete(link.buffer, link.next)
+		ready = append(ready, next)
+		link.next++
+	}
+	return ready
+}
+
+// unacknowledged returns the pending messages sent before the given time in
+// sequence order and marks them as sent now, it expects the guard to be locked.
+func (link *Link) unacknowledged(before time.Time) []Message {
+
+	var messages []Message
+	for _, pending := range link.pending {
+
+		if !pending.sentAt.After(before) {
+
+			pending.sentAt = time.Now()
+			messages = append(messages, pending.message)
+		}
+	}
+
+	sort.Slice(messages, func(i int, j int) bool {
+
+		return messages[i].Sequence < messages[j].Sequence
+	})
+	return messages
+}
+
+func (link *Link) retransmit() {
+
+	var ticker = time.NewTicker(RetransmitTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+
+		select {
+		case <-link.stop:
+			return
+		case <-ticker.C:
+		}
+
+		link.guard.Lock()
+		var messages = link.unacknowledged(time.Now().Add(-RetransmitTimeout))
+		link.guard.Unlock()
+
+		for _, message := range messages {
+
+			Printf("[Log] [Reliable]: retransmitting <%s> #%d to <%s>\n", StringFor(message.Command), message.Sequence, link.peer)
+			link.transmit(message)
+		}
+	}
+}
+
+func (link *Link) transmit(message Message) {
+
+	link.chaos.Send(link.peer, message, link.write, link.Reset)
+}
+
+func (link *Link) write(message Message) {
+
+	link.guard.Lock()
+	var writer = link.writer
+	link.guard.Unlock()
+
+	if writer == nil {
+
+		return // sent again once the link is reconnected
+	}
+	writer.enqueue(message)
+}
+
+func writerFor(peer string, connection net.Conn, encoder *Encoder) *writer {
+
+	var writer = new(writer)
+	writer.peer = peer
+	writer.connection = connection
+	writer.encoder = encoder
+	writer.queued = make(map[queueKey]bool)
+	writer.wake = make(chan bool, 1)
+	writer.stop = make(chan bool)
+
+	go writer.run()
+	return writer
+}
+
+// enqueue skips a message that still waits to be written, retransmissions of
+// a peer that does not read would pile up otherwise.
+func (writer *writer) enqueue(message Message) {
+
+	var key = queueKey{ack: message.Command == AckCommand, sequence: message.Sequence}
+
+	writer.guard.Lock()
+	if !writer.queued[key] {
+
+		writer.queued[key] = true
+		writer.queue = append(writer.queue, message)
+	}
+	writer.guard.Unlock()
+
+	select {
+	case writer.wake <- true:
+	default: // the writer is awake already
+	}
+}
+
+// close stops the writer and closes the connection, which also ends an
+// Encode that is blocked on it.
+func (writer *writer) close() {
+
+	writer.once.Do(func() { close(writer.stop) })
+	writer.connection.Close()
+}
+
+func (writer *writer) run() {
+
+	for {
+
+		select {
+		case <-writer.stop:
+			return
+		case <-writer.wake:
+		}
+
+		for {
+
+			writer.guard.Lock()
+			if len(writer.queue) == 0 {
+
+				writer.guard.Unlock()
+				break
+			}
+			var message = writer.queue[0]
+			writer.queue = writer.queue[1:]
+			delete(writer.queued, queueKey{ack: message.Command == AckCommand, sequence: message.Sequence})
+			writer.guard.Unlock()
+
+			if encodingError := writer.encoder.Encode(message); encodingError != nil {
+
+				// the reading side notices the closed connection and reconnects
+				Printf("[Log] [Reliable]: sending <%s> to <%s> failed: %v\n", StringFor(message.Command), writer.peer, encodingError)
+				writer.connection.Close()
+				return
+			}
+		}
+	}
+}
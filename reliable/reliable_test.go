@@ -0,0 +1,154 @@
+//
+//  reliable_test.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+package reliable
+
+import . "encoding/gob"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/message"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/bfs/command"
+
+import "net"
+import "reflect"
+import "testing"
+import "time"
+
+func sequencesOf(messages []Message) []uint64 {
+
+	var sequences = []uint64{}
+	for _, message := range messages {
+
+		sequences = append(sequences, message.Sequence)
+	}
+	return sequences
+}
+
+func TestReceiveOrderAndDeduplication(t *testing.T) {
+
+	var tests = []struct {
+		name     string
+		arrivals []uint64
+		ready    [][]uint64 // sequences handed out after each arrival
+	}{
+		{"in order", []uint64{1, 2, 3}, [][]uint64{{1}, {2}, {3}}},
+		{"reordered", []uint64{2, 3, 1, 4}, [][]uint64{{}, {}, {1, 2, 3}, {4}}},
+		{"duplicate of a handed out message", []uint64{1, 1, 2}, [][]uint64{{1}, {}, {2}}},
+		{"duplicate of a buffered message", []uint64{3, 3, 2, 1}, [][]uint64{{}, {}, {}, {1, 2, 3}}},
+		{"gap stays buffered", []uint64{1, 3, 4}, [][]uint64{{1}, {}, {}}},
+		{"not sent over a link", []uint64{0, 0}, [][]uint64{{0}, {0}}},
+	}
+
+	for _, test := range tests {
+
+		var link = LinkBetween("a", "b", nil)
+		for index, sequence := range test.arrivals {
+
+			var ready = link.Receive(Message{Sender: "b", Receiver: "a", Command: LabelCommand, Sequence: sequence})
+			if sequences := sequencesOf(ready); !reflect.DeepEqual(sequences, test.ready[index]) {
+
+				t.Errorf("%s: arrival %d of #%d handed out %v, expected %v", test.name, index, sequence, sequences, test.ready[index])
+			}
+		}
+		link.Close()
+	}
+}
+
+func TestAttachRefusesStaleEpochs(t *testing.T) {
+
+	var link = LinkBetween("a", "b", nil)
+	defer link.Close()
+
+	var tests = []struct {
+		name     string
+		epoch    uint64
+		attached bool
+	}{
+		{"first connection", 0, true},
+		{"second link to the same peer", 0, false},
+		{"reconnect", 1, true},
+		{"late reconnect", 1, false},
+		{"skipped epoch", 3, true},
+		{"older epoch", 2, false},
+	}
+
+	for _, test := range tests {
+
+		var local, remote = net.Pipe()
+		if attached := link.Attach(local, NewEncoder(local), test.epoch); attached != test.attached {
+
+			t.Errorf("%s: attached %v, expected %v", test.name, attached, test.attached)
+		}
+		remote.Close()
+	}
+
+	if epoch := link.Epoch(); epoch != 3 {
+
+		t.Errorf("link has epoch %d, expected 3", epoch)
+	}
+}
+
+func TestAttachSendsUnacknowledgedMessages(t *testing.T) {
+
+	var link = LinkBetween("a", "b", nil)
+	defer link.Close()
+
+	// sent while no connection is attached
+	link.Send(Message{Sender: "a", Receiver: "b", Command: LabelCommand})
+	link.Send(Message{Sender: "a", Receiver: "b", Command: EndCommand})
+	link.Receive(Message{Sender: "b", Receiver: "a", Command: AckCommand, Sequence: 1})
+
+	var local, remote = net.Pipe()
+	defer remote.Close()
+
+	var received = make(chan Message, 1)
+	go func() {
+
+		var message Message
+		if decodingError := NewDecoder(remote).Decode(&message); decodingError == nil {
+
+			received <- message
+		}
+		close(received)
+	}()
+
+	link.Attach(local, NewEncoder(local), 1)
+
+	var message, ok = <-received
+	if !ok || message.Sequence != 2 || message.Command != EndCommand {
+
+		t.Errorf("received %v, expected the unacknowledged message #2", message)
+	}
+}
+
+func TestPeerThatDoesNotReadBlocksNoCaller(t *testing.T) {
+
+	var link = LinkBetween("a", "b", nil)
+	defer link.Close()
+
+	// nobody reads remote, so every write to local blocks
+	var local, remote = net.Pipe()
+	defer remote.Close()
+	link.Attach(local, NewEncoder(local), 1)
+
+	var done = make(chan bool)
+	go func() {
+
+		for sequence := uint64(1); sequence <= 100; sequence++ {
+
+			link.Send(Message{Sender: "a", Receiver: "b", Command: LabelCommand})
+			link.Receive(Message{Sender: "b", Receiver: "a", Command: LabelCommand, Sequence: sequence})
+			link.Epoch()
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("sending, receiving or acknowledging blocked on a peer that does not read")
+	}
+}
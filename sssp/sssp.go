@@ -0,0 +1,196 @@
+//
+//  sssp.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+// Package sssp computes a shortest-path tree on weighted links with the
+// Chandy-Misra algorithm.
+package sssp
+
+import . "fmt"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/bfs/command"
+
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/algorithm"
+
+import "sync"
+import "encoding/gob"
+
+// Node relaxes the distance estimates like Bellman-Ford: it adopts every
+// shorter distance it hears of and announces it to its neighbors. Termination
+// is detected with Dijkstra-Scholten exactly like bfs.AsyncNode: every
+// distance is acknowledged, and the distance that engaged a node is
+// acknowledged only after all of its own distances were acknowledged.
+type Node struct {
+	guard         sync.Mutex
+	host          algorithm.Host
+	id            string
+	parentID      string
+	distance      float64
+	reached       bool
+	root          bool
+	neighbors     []string
+	weights       map[string]float64
+	lastDistances map[string]float64 // smallest distance received from each neighbor
+	children      map[string]bool    // whether the neighbor's latest distance named us as parent
+	engagedWith   string             // sender of the distance we have not acknowledged yet
+	deficit       int                // number of own distances not acknowledged yet
+	completed     bool
+	messages      int
+}
+
+// Distance is the value of DistanceCommand.
+type Distance struct {
+	Distance float64
+	ParentID string
+}
+
+type Result struct {
+	ID       string
+	ParentID string
+	Length   float64 // length of the shortest path from the root
+	Children []string
+	Messages int
+}
+
+func init() {
+
+	gob.Register(Distance{})
+	gob.Register(Result{})
+	algorithm.Register("sssp", func() algorithm.Algorithm { return new(Node) })
+}
+
+func (node *Node) Init(environment algorithm.Environment) {
+
+	node.guard.Lock()
+	node.host = environment.Host
+	node.id = environment.ID
+	node.parentID = ""
+	node.neighbors = append([]string{}, environment.Neighbors...)
+	node.weights = environment.Weights
+	node.lastDistances = make(map[string]float64)
+	node.children = make(map[string]bool)
+	node.guard.Unlock()
+}
+
+func (node *Node) HandleMessage(sender string, receiver string, command uint8, value interface{}) {
+
+	node.guard.Lock()
+	switch command {
+
+	case InitCommand:
+		node.root = true
+		node.reached = true
+		node.parentID = node.id
+		node.distance = 0
+		node.broadcast()
+
+	case DistanceCommand:
+		var distance = value.(Distance)
+
+		// distances of one neighbor only get smaller, an older one may arrive late
+		if lastDistance, known := node.lastDistances[sender]; !known || distance.Distance < lastDistance {
+
+			node.lastDistances[sender] = distance.Distance
+			node.children[sender] = distance.ParentID == node.id
+		}
+
+		if candidate := distance.Distance + node.weights[sender]; !node.reached || candidate < node.distance {
+
+			node.reached = true
+			node.distance = candidate
+			node.parentID = sender
+			node.broadcast()
+		}
+
+		if !node.root && len(node.engagedWith) == 0 {
+
+			node.engagedWith = sender
+
+		} else {
+
+			node.send(sender, DistanceAckCommand, nil)
+		}
+
+	case DistanceAckCommand:
+		node.deficit--
+
+	default:
+		Printf("[SSSP Algorithm]: Unknown command \"%d\"- do nothing\n", command)
+	}
+	node.checkTermination()
+	node.guard.Unlock()
+}
+
+func (node *Node) Result() interface{} {
+
+	node.guard.Lock()
+	var children []string
+	for _, neighborID := range node.neighbors {
+
+		if node.children[neighborID] {
+
+			children = append(children, neighborID)
+		}
+	}
+	var result = Result{ID: node.id, ParentID: node.parentID, Length: node.distance, Children: children, Messages: node.messages}
+	node.guard.Unlock()
+	return result
+}
+
+// The following helpers expect the guard to be locked by the caller.
+func (node *Node) broadcast() {
+
+	// the parent gets the distance as well, this is how it learns about its children
+	for _, neighborID := range node.neighbors {
+
+		node.deficit++
+		node.send(neighborID, DistanceCommand, Distance{Distance: node.distance, ParentID: node.parentID})
+	}
+}
+
+func (node *Node) checkTermination() {
+
+	if node.deficit > 0 {
+		return
+	}
+
+	if len(node.engagedWith) > 0 {
+
+		node.send(node.engagedWith, DistanceAckCommand, nil)
+		node.engagedWith = ""
+
+	} else if node.root && !node.completed {
+
+		node.completed = true
+		node.send("server", CompleteCommand, nil)
+	}
+}
+
+func (node *Node) send(receiver string, command uint8, value interface{}) {
+
+	node.messages++
+	node.host.SendMessage(node.id, receiver, command, value)
+}
+
+func (result Result) Parent() string {
+
+	return result.ParentID
+}
+
+func (result Result) Distance() float64 {
+
+	return result.Length
+}
+
+func (result Result) MessageCount() int {
+
+	return result.Messages
+}
+
+func (result Result) String() string {
+
+	return Sprintf("<ID: %s Parent: %s Distance: %g Children: %v>", result.ID, result.ParentID, result.Length, result.Children)
+}
@@ -0,0 +1,108 @@
+//
+//  sssp_test.go
+//
+//  Created by Adrian Zubarev.
+//  Copyright © 2016 Adrian Zubarev.
+//  All rights reserved.
+//
+
+package sssp
+
+import . "fmt"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/bfs/command"
+import . "github.com/DevAndArtist/Distributed-BFS-in-Go/graph"
+
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/algorithm"
+import "github.com/DevAndArtist/Distributed-BFS-in-Go/algorithm/algorithmtest"
+
+import "testing"
+
+var idOf = algorithmtest.IDOf
+var graphOf = algorithmtest.GraphOf
+
+// relax starts at the root and delivers every message.
+func relax(graph *Graph, root Vertex, seed int64) ([]algorithmtest.Sent, map[Vertex]*Node) {
+
+	var network = algorithmtest.NetworkWith(seed)
+	var nodes = make(map[Vertex]*Node)
+	for vertex, node := range algorithmtest.NodesOf(graph, network, func() algorithm.Algorithm { return new(Node) }) {
+
+		nodes[vertex] = node.(*Node)
+	}
+
+	network.SendMessage("server", idOf(root), InitCommand, nil)
+	network.Deliver()
+	return network.ServerMessages(), nodes
+}
+
+func TestNodeFindsTheShortestPaths(t *testing.T) {
+
+	var tests = []struct {
+		name  string
+		graph *Graph
+	}{
+		{"single edge", graphOf([][3]int{{0, 1, 3}})},
+		{"detour is shorter", graphOf([][3]int{{0, 1, 10}, {0, 2, 1}, {2, 3, 1}, {3, 1, 1}})},
+		{"cycle", graphOf([][3]int{{0, 1, 1}, {1, 2, 5}, {2, 3, 1}, {3, 4, 1}, {4, 0, 2}})},
+		{"complete", graphOf([][3]int{{0, 1, 7}, {0, 2, 2}, {0, 3, 9}, {1, 2, 3}, {1, 3, 1}, {2, 3, 6}})},
+		{"random", algorithmtest.RandomGraph(12, 10, 9, 1)},
+		{"random dense", algorithmtest.RandomGraph(10, 25, 20, 2)},
+	}
+
+	for _, test := range tests {
+
+		var distances = test.graph.ShortestDistances(0)
+
+		for seed := int64(0); seed < 10; seed++ {
+
+			var name = Sprintf("%s, seed %d", test.name, seed)
+			var server, nodes = relax(test.graph, 0, seed)
+
+			// Dijkstra-Scholten: once the root knows, every distance was acknowledged
+			if len(server) != 1 || server[0].Sender != idOf(0) || server[0].Command != CompleteCommand {
+
+				t.Errorf("%s: the server received %v, expected a single completion of the root", name, server)
+
+			} else if server[0].InFlight != 0 {
+
+				t.Errorf("%s: the root completed with %d messages in flight", name, server[0].InFlight)
+			}
+
+			var results = make(map[string]Result)
+			for vertex, node := range nodes {
+
+				results[idOf(vertex)] = node.Result().(Result)
+				if node.deficit != 0 || node.engagedWith != "" {
+
+					t.Errorf("%s: vertex %d ends with deficit %d engaged with %q", name, vertex, node.deficit, node.engagedWith)
+				}
+			}
+
+			for _, vertex := range test.graph.Vertices() {
+
+				var result = results[idOf(vertex)]
+				if result.Length != float64(distances[vertex]) {
+
+					t.Errorf("%s: vertex %d has length %g, expected %g", name, vertex, result.Length, distances[vertex])
+				}
+
+				if vertex == 0 {
+
+					continue
+				}
+
+				var parent = results[result.ParentID]
+				var listed = false
+				for _, childID := range parent.Children {
+
+					listed = listed || childID == idOf(vertex)
+				}
+
+				if !listed || parent.Length+nodes[vertex].weights[result.ParentID] != result.Length {
+
+					t.Errorf("%s: vertex %d at %g is no child of %s at %g with children %v", name, vertex, result.Length, result.ParentID, parent.Length, parent.Children)
+				}
+			}
+		}
+	}
+}